@@ -0,0 +1,138 @@
+package echoSwagger
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v5"
+)
+
+// BasicAuthConfig stores the credentials required to access the documentation.
+type BasicAuthConfig struct {
+	Username string
+	Password string
+
+	// The realm sent in the WWW-Authenticate header. Default is `Restricted`.
+	Realm string
+}
+
+// accessControl guards every endpoint of a handler.
+type accessControl struct {
+	prefixes   []netip.Prefix
+	basicAuth  *BasicAuthConfig
+	authorizer func(*echo.Context) bool
+}
+
+func newAccessControl(config *Config) *accessControl {
+	a := &accessControl{
+		basicAuth:  config.BasicAuth,
+		authorizer: config.Authorizer,
+	}
+
+	if len(config.AllowedIPs) > 0 {
+		// Deny everyone rather than no one if none of the entries are valid.
+		a.prefixes = []netip.Prefix{}
+	}
+	for _, allowed := range config.AllowedIPs {
+		if prefix, err := parsePrefix(allowed); err == nil {
+			a.prefixes = append(a.prefixes, prefix)
+		}
+	}
+
+	return a
+}
+
+// parsePrefix parses a CIDR or a single IP address.
+func parsePrefix(s string) (netip.Prefix, error) {
+	if strings.Contains(s, "/") {
+		prefix, err := netip.ParsePrefix(s)
+		return prefix.Masked(), err
+	}
+
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// check returns the status code of the response denying the request, or 0 if the request
+// is allowed.
+func (a *accessControl) check(c *echo.Context) int {
+	if a.prefixes != nil && !a.allowIP(clientIP(c)) {
+		return http.StatusForbidden
+	}
+
+	if a.basicAuth != nil && !a.allowBasicAuth(c) {
+		realm := a.basicAuth.Realm
+		if realm == "" {
+			realm = "Restricted"
+		}
+		c.Response().Header().Set("WWW-Authenticate", "Basic realm="+strconv.Quote(realm))
+
+		return http.StatusUnauthorized
+	}
+
+	if a.authorizer != nil && !a.authorizer(c) {
+		return http.StatusForbidden
+	}
+
+	return 0
+}
+
+// clientIP returns the address of the client: echo.Context.RealIP if the Echo instance has
+// an IPExtractor, the remote address otherwise, since the X-Forwarded-For and X-Real-IP
+// headers RealIP falls back to can be sent by any client.
+func clientIP(c *echo.Context) string {
+	if e := c.Echo(); e != nil && e.IPExtractor != nil {
+		return c.RealIP()
+	}
+
+	return c.Request().RemoteAddr
+}
+
+func (a *accessControl) allowIP(ip string) bool {
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+
+	for _, prefix := range a.prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (a *accessControl) allowBasicAuth(c *echo.Context) bool {
+	username, password, ok := c.Request().BasicAuth()
+	if !ok {
+		return false
+	}
+
+	// Compare digests so that neither the content nor the length of the credentials leaks.
+	usernameMatch := secureCompare(username, a.basicAuth.Username)
+	passwordMatch := secureCompare(password, a.basicAuth.Password)
+
+	return usernameMatch&passwordMatch == 1
+}
+
+func secureCompare(given, expected string) int {
+	givenSum := sha256.Sum256([]byte(given))
+	expectedSum := sha256.Sum256([]byte(expected))
+
+	return subtle.ConstantTimeCompare(givenSum[:], expectedSum[:])
+}
@@ -0,0 +1,142 @@
+package echoSwagger
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccessOptions(t *testing.T) {
+	var cfg Config
+	BasicAuth("admin", "secret")(&cfg)
+	assert.Equal(t, &BasicAuthConfig{Username: "admin", Password: "secret"}, cfg.BasicAuth)
+
+	Authorizer(func(*echo.Context) bool { return true })(&cfg)
+	assert.NotNil(t, cfg.Authorizer)
+
+	AllowIPs("10.0.0.0/8", "127.0.0.1")(&cfg)
+	assert.Equal(t, []string{"10.0.0.0/8", "127.0.0.1"}, cfg.AllowedIPs)
+}
+
+var protectedPaths = []string{"/index.html", "/doc.json", "/doc.yaml", "/swagger-ui.css", "/"}
+
+func TestBasicAuth(t *testing.T) {
+	for _, wrap := range []func(options ...func(*Config)) echo.HandlerFunc{EchoWrapHandler, EchoWrapHandlerV3} {
+		router := echo.New()
+		router.GET("/*", wrap(BasicAuth("admin", "secret"), Provider(StaticDoc([]byte(`{}`)))))
+
+		for _, path := range protectedPaths {
+			w1 := performRequest(http.MethodGet, path, router)
+			assert.Equal(t, http.StatusUnauthorized, w1.Code, path)
+			assert.Equal(t, `Basic realm="Restricted"`, w1.Header().Get("WWW-Authenticate"), path)
+
+			r := httptest.NewRequest(http.MethodGet, path, nil)
+			r.SetBasicAuth("admin", "wrong")
+			w2 := httptest.NewRecorder()
+			router.ServeHTTP(w2, r)
+			assert.Equal(t, http.StatusUnauthorized, w2.Code, path)
+
+			r = httptest.NewRequest(http.MethodGet, path, nil)
+			r.SetBasicAuth("admin", "secret")
+			w3 := httptest.NewRecorder()
+			router.ServeHTTP(w3, r)
+			assert.Contains(t, []int{http.StatusOK, http.StatusMovedPermanently}, w3.Code, path)
+		}
+	}
+}
+
+func TestAuthorizer(t *testing.T) {
+	authorizer := func(c *echo.Context) bool {
+		return c.Request().Header.Get("X-Staff") == "true"
+	}
+
+	for _, wrap := range []func(options ...func(*Config)) echo.HandlerFunc{EchoWrapHandler, EchoWrapHandlerV3} {
+		router := echo.New()
+		router.GET("/*", wrap(Authorizer(authorizer), Provider(StaticDoc([]byte(`{}`)))))
+
+		for _, path := range protectedPaths {
+			assert.Equal(t, http.StatusForbidden, performRequest(http.MethodGet, path, router).Code, path)
+
+			r := httptest.NewRequest(http.MethodGet, path, nil)
+			r.Header.Set("X-Staff", "true")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, r)
+			assert.Contains(t, []int{http.StatusOK, http.StatusMovedPermanently}, w.Code, path)
+		}
+	}
+}
+
+func TestAllowIPs(t *testing.T) {
+	for _, wrap := range []func(options ...func(*Config)) echo.HandlerFunc{EchoWrapHandler, EchoWrapHandlerV3} {
+		router := echo.New()
+		router.GET("/*", wrap(AllowIPs("10.0.0.0/8", "::1"), Provider(StaticDoc([]byte(`{}`)))))
+
+		for _, path := range protectedPaths {
+			r := httptest.NewRequest(http.MethodGet, path, nil)
+			r.RemoteAddr = "192.168.1.10:4321"
+			w1 := httptest.NewRecorder()
+			router.ServeHTTP(w1, r)
+			assert.Equal(t, http.StatusForbidden, w1.Code, path)
+
+			r = httptest.NewRequest(http.MethodGet, path, nil)
+			r.RemoteAddr = "10.1.2.3:4321"
+			w2 := httptest.NewRecorder()
+			router.ServeHTTP(w2, r)
+			assert.Contains(t, []int{http.StatusOK, http.StatusMovedPermanently}, w2.Code, path)
+
+			r = httptest.NewRequest(http.MethodGet, path, nil)
+			r.RemoteAddr = "[::1]:4321"
+			w3 := httptest.NewRecorder()
+			router.ServeHTTP(w3, r)
+			assert.Contains(t, []int{http.StatusOK, http.StatusMovedPermanently}, w3.Code, path)
+		}
+	}
+}
+
+func TestAllowIPsSpoofedHeaders(t *testing.T) {
+	router := echo.New()
+	router.GET("/*", EchoWrapHandler(AllowIPs("10.0.0.0/8")))
+
+	for _, header := range []string{echo.HeaderXForwardedFor, echo.HeaderXRealIP} {
+		r := httptest.NewRequest(http.MethodGet, "/index.html", nil)
+		r.RemoteAddr = "203.0.113.9:4321"
+		r.Header.Set(header, "10.1.2.3")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		assert.Equal(t, http.StatusForbidden, w.Code, header)
+	}
+
+	// Forwarded addresses are only trusted through the IPExtractor of the Echo instance.
+	_, private, _ := net.ParseCIDR("192.168.0.0/16")
+	router.IPExtractor = echo.ExtractIPFromXFFHeader(echo.TrustIPRange(private))
+
+	r := httptest.NewRequest(http.MethodGet, "/index.html", nil)
+	r.RemoteAddr = "192.168.1.1:4321"
+	r.Header.Set(echo.HeaderXForwardedFor, "10.1.2.3")
+	w1 := httptest.NewRecorder()
+	router.ServeHTTP(w1, r)
+	assert.Equal(t, http.StatusOK, w1.Code)
+
+	r = httptest.NewRequest(http.MethodGet, "/index.html", nil)
+	r.RemoteAddr = "203.0.113.9:4321"
+	r.Header.Set(echo.HeaderXForwardedFor, "10.1.2.3")
+	w2 := httptest.NewRecorder()
+	router.ServeHTTP(w2, r)
+	assert.Equal(t, http.StatusForbidden, w2.Code)
+}
+
+func TestAllowIPsInvalid(t *testing.T) {
+	router := echo.New()
+	router.GET("/*", EchoWrapHandler(AllowIPs("not-an-ip")))
+
+	assert.Equal(t, http.StatusForbidden, performRequest(http.MethodGet, "/index.html", router).Code)
+
+	_, err := New(AllowIPs("10.0.0.0/8", "10.0.0.300"), Provider(StaticDoc([]byte(`{}`))))
+	assert.EqualError(t, err, `echoSwagger: invalid AllowedIPs entry "10.0.0.300", want an IP address or a CIDR range`)
+	_, err = NewV3(AllowIPs("10.0.0.0/8", "::1"), Provider(StaticDoc([]byte(`{}`))))
+	assert.NoError(t, err)
+}
@@ -0,0 +1,161 @@
+package echoSwagger
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v5"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	mimeOpenAPIJSON = "application/openapi+json; charset=utf-8"
+	mimeOpenAPIYAML = "application/openapi+yaml; charset=utf-8"
+)
+
+// renderedDoc is an API definition rendered as JSON or YAML.
+type renderedDoc struct {
+	body []byte
+	etag string
+
+	// The Content-Type of the document, if it differs from the one of its file extension.
+	contentType string
+
+	modTime time.Time
+}
+
+// docCache renders the API definitions of a handler and caches them per instance.
+type docCache struct {
+	provider     DocProvider
+	converted    DocProvider
+	cacheable    bool
+	cacheControl string
+
+	// Serve OpenAPI 3.1 documents with the application/openapi+json and
+	// application/openapi+yaml media types.
+	openAPIMediaTypes bool
+
+	mu   sync.Mutex
+	docs map[string]*renderedDoc
+}
+
+func newDocCache(config *Config, fallback DocProvider, openAPIMediaTypes bool) *docCache {
+	provider := config.docProvider(fallback)
+
+	return &docCache{
+		provider:          provider,
+		converted:         openAPI3Doc(provider),
+		cacheable:         config.docCacheable(fallback),
+		cacheControl:      config.DocCacheControl,
+		openAPIMediaTypes: openAPIMediaTypes,
+		docs:              map[string]*renderedDoc{},
+	}
+}
+
+// docCacheable reports whether the documents of the handler can be rendered once. They
+// cannot when they depend on the request, which the documents of user-supplied providers
+// may do unless caching is turned on with DocCache(true).
+func (config *Config) docCacheable(fallback DocProvider) bool {
+	if config.DynamicHost || config.RequestFilter != nil {
+		return false
+	}
+	if config.DocCache != nil {
+		return *config.DocCache
+	}
+
+	provider := config.DocProvider
+	if provider == nil {
+		provider = fallback
+	}
+	_, static := provider.(staticDoc)
+
+	return static
+}
+
+// read renders the doc.json, doc.yaml, openapi.json or openapi.yaml of the given instance.
+func (d *docCache) read(c *echo.Context, instanceName, name string, modTime time.Time) (*renderedDoc, error) {
+	provider := d.provider
+	if strings.HasPrefix(name, "openapi.") {
+		provider = d.converted
+	}
+
+	body, err := provider.ReadDoc(c, instanceName)
+	if err != nil {
+		return nil, err
+	}
+
+	var contentType string
+	if d.openAPIMediaTypes && isOpenAPI31(docVersion(body)) {
+		contentType = mimeOpenAPIJSON
+	}
+
+	if strings.HasSuffix(name, ".yaml") {
+		if body, err = yaml.JSONToYAML(body); err != nil {
+			return nil, err
+		}
+
+		if contentType != "" {
+			contentType = mimeOpenAPIYAML
+		}
+	}
+
+	sum := sha256.Sum256(body)
+
+	return &renderedDoc{
+		body:        body,
+		etag:        `"` + hex.EncodeToString(sum[:16]) + `"`,
+		contentType: contentType,
+		modTime:     modTime,
+	}, nil
+}
+
+// render returns the requested document of the given instance.
+func (d *docCache) render(c *echo.Context, instanceName, name string) (*renderedDoc, error) {
+	if !d.cacheable {
+		return d.read(c, instanceName, name, time.Time{})
+	}
+
+	key := instanceName + "/" + name
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if doc, ok := d.docs[key]; ok {
+		return doc, nil
+	}
+
+	doc, err := d.read(c, instanceName, name, time.Now().UTC().Truncate(time.Second))
+	if err != nil {
+		return nil, err
+	}
+	d.docs[key] = doc
+
+	return doc, nil
+}
+
+// serve writes the requested document of the given instance, answering conditional
+// requests with 304 Not Modified.
+func (d *docCache) serve(c *echo.Context, instanceName, name string) error {
+	doc, err := d.render(c, instanceName, name)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, err.Error())
+	}
+
+	header := c.Response().Header()
+	header.Set("ETag", doc.etag)
+	if doc.contentType != "" {
+		header.Set("Content-Type", doc.contentType)
+	}
+	if d.cacheControl != "" {
+		header.Set("Cache-Control", d.cacheControl)
+	}
+
+	http.ServeContent(c.Response(), c.Request(), name, doc.modTime, bytes.NewReader(doc.body))
+
+	return nil
+}
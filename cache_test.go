@@ -0,0 +1,139 @@
+package echoSwagger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocCacheOptions(t *testing.T) {
+	var cfg Config
+	DocCache(true)(&cfg)
+	DocCacheControl("max-age=60")(&cfg)
+	AssetsCacheControl("public, max-age=86400")(&cfg)
+	assert.Equal(t, true, *cfg.DocCache)
+	assert.Equal(t, "max-age=60", cfg.DocCacheControl)
+	assert.Equal(t, "public, max-age=86400", cfg.AssetsCacheControl)
+
+	newCfg := newConfig()
+	assert.Nil(t, newCfg.DocCache)
+	assert.Equal(t, "no-cache", newCfg.DocCacheControl)
+	assert.Empty(t, newCfg.AssetsCacheControl)
+}
+
+func countingProvider(calls *int32) DocProvider {
+	return DocProviderFunc(func(_ *echo.Context, _ string) ([]byte, error) {
+		atomic.AddInt32(calls, 1)
+		return []byte((&mockedSwag{}).ReadDoc()), nil
+	})
+}
+
+func TestDocCache(t *testing.T) {
+	for _, wrap := range []func(options ...func(*Config)) echo.HandlerFunc{EchoWrapHandler, EchoWrapHandlerV3} {
+		var calls int32
+
+		router := echo.New()
+		router.GET("/*", wrap(Provider(countingProvider(&calls)), DocCache(true)))
+
+		w1 := performRequest(http.MethodGet, "/doc.json", router)
+		assert.Equal(t, http.StatusOK, w1.Code)
+		assert.Equal(t, "application/json; charset=utf-8", w1.Header().Get("Content-Type"))
+		assert.Equal(t, "no-cache", w1.Header().Get("Cache-Control"))
+		assert.Equal(t, (&mockedSwag{}).ReadDoc(), w1.Body.String())
+
+		etag := w1.Header().Get("ETag")
+		assert.Regexp(t, `^"[0-9a-f]{32}"$`, etag)
+		lastModified := w1.Header().Get("Last-Modified")
+		assert.NotEmpty(t, lastModified)
+
+		w2 := performRequest(http.MethodGet, "/doc.yaml", router)
+		assert.Equal(t, http.StatusOK, w2.Code)
+		assert.NotEqual(t, etag, w2.Header().Get("ETag"))
+
+		r := httptest.NewRequest(http.MethodGet, "/doc.json", nil)
+		r.Header.Set("If-None-Match", etag)
+		w3 := httptest.NewRecorder()
+		router.ServeHTTP(w3, r)
+		assert.Equal(t, http.StatusNotModified, w3.Code)
+		assert.Empty(t, w3.Body.String())
+
+		r = httptest.NewRequest(http.MethodGet, "/doc.json", nil)
+		r.Header.Set("If-Modified-Since", time.Now().UTC().Add(time.Hour).Format(http.TimeFormat))
+		w4 := httptest.NewRecorder()
+		router.ServeHTTP(w4, r)
+		assert.Equal(t, http.StatusNotModified, w4.Code)
+
+		r = httptest.NewRequest(http.MethodGet, "/doc.json", nil)
+		r.Header.Set("If-None-Match", `"stale"`)
+		w5 := httptest.NewRecorder()
+		router.ServeHTTP(w5, r)
+		assert.Equal(t, http.StatusOK, w5.Code)
+		assert.Equal(t, etag, w5.Header().Get("ETag"))
+
+		assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	}
+}
+
+func TestDocCacheDisabled(t *testing.T) {
+	var calls int32
+
+	router := echo.New()
+	router.GET("/*", EchoWrapHandler(Provider(countingProvider(&calls)), DocCache(false), DocCacheControl("")))
+
+	w1 := performRequest(http.MethodGet, "/doc.json", router)
+	assert.Equal(t, http.StatusOK, w1.Code)
+	assert.NotEmpty(t, w1.Header().Get("ETag"))
+	assert.Empty(t, w1.Header().Get("Last-Modified"))
+	assert.Empty(t, w1.Header().Get("Cache-Control"))
+
+	r := httptest.NewRequest(http.MethodGet, "/doc.json", nil)
+	r.Header.Set("If-None-Match", w1.Header().Get("ETag"))
+	w2 := httptest.NewRecorder()
+	router.ServeHTTP(w2, r)
+	assert.Equal(t, http.StatusNotModified, w2.Code)
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestDocCacheDefault(t *testing.T) {
+	tenantDoc := DocProviderFunc(func(c *echo.Context, _ string) ([]byte, error) {
+		return []byte(`{"swagger": "2.0", "info": {"title": "` + c.Request().Header.Get("X-Tenant") + `"}}`), nil
+	})
+
+	for _, wrap := range []func(options ...func(*Config)) echo.HandlerFunc{EchoWrapHandler, EchoWrapHandlerV3} {
+		router := echo.New()
+		router.GET("/*", wrap(Provider(tenantDoc)))
+
+		// Documents of user-supplied providers may differ per request, so they are not cached.
+		for _, tenant := range []string{"alice", "bob"} {
+			r := httptest.NewRequest(http.MethodGet, "/doc.json", nil)
+			r.Header.Set("X-Tenant", tenant)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, r)
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.JSONEq(t, `{"swagger": "2.0", "info": {"title": "`+tenant+`"}}`, w.Body.String())
+			assert.Empty(t, w.Header().Get("Last-Modified"))
+		}
+
+		// The documents of the built-in providers are.
+		router = echo.New()
+		router.GET("/*", wrap(Provider(StaticDoc([]byte(`{"swagger": "2.0"}`)))))
+		assert.NotEmpty(t, performRequest(http.MethodGet, "/doc.json", router).Header().Get("Last-Modified"))
+	}
+}
+
+func TestAssetsCacheControl(t *testing.T) {
+	for _, wrap := range []func(options ...func(*Config)) echo.HandlerFunc{EchoWrapHandler, EchoWrapHandlerV3} {
+		router := echo.New()
+		router.GET("/*", wrap(AssetsCacheControl("public, max-age=86400")))
+
+		w := performRequest(http.MethodGet, "/swagger-ui.css", router)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "public, max-age=86400", w.Header().Get("Cache-Control"))
+	}
+}
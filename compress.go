@@ -0,0 +1,149 @@
+package echoSwagger
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/fs"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/labstack/echo/v5"
+)
+
+// compressibleExts lists the asset extensions worth compressing.
+var compressibleExts = map[string]bool{
+	".css":  true,
+	".html": true,
+	".js":   true,
+	".json": true,
+	".map":  true,
+}
+
+// encoders lists the supported content encodings by order of preference.
+var encoders = []struct {
+	name     string
+	compress func(w io.Writer) io.WriteCloser
+}{
+	{"br", func(w io.Writer) io.WriteCloser { return brotli.NewWriterLevel(w, brotli.DefaultCompression) }},
+	{"gzip", func(w io.Writer) io.WriteCloser {
+		zw, _ := gzip.NewWriterLevel(w, gzip.BestCompression)
+		return zw
+	}},
+}
+
+// compressedAssets serves the compressed variants of the Swagger UI assets. Every variant
+// is compressed on first use and kept in memory.
+type compressedAssets struct {
+	fsys fs.FS
+
+	mu       sync.Mutex
+	variants map[string][]byte
+}
+
+func newCompressedAssets(fsys fs.FS) *compressedAssets {
+	return &compressedAssets{
+		fsys:     fsys,
+		variants: map[string][]byte{},
+	}
+}
+
+// variant returns the asset compressed with the given encoding, or nil if compressing does
+// not make it smaller.
+func (a *compressedAssets) variant(name, encoding string) ([]byte, error) {
+	key := encoding + ":" + name
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if b, ok := a.variants[key]; ok {
+		return b, nil
+	}
+
+	raw, err := fs.ReadFile(a.fsys, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	for _, encoder := range encoders {
+		if encoder.name != encoding {
+			continue
+		}
+
+		w := encoder.compress(&buf)
+		if _, err = w.Write(raw); err != nil {
+			return nil, err
+		}
+		if err = w.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	var b []byte
+	if buf.Len() > 0 && buf.Len() < len(raw) {
+		b = buf.Bytes()
+	}
+	a.variants[key] = b
+
+	return b, nil
+}
+
+// serve writes the asset compressed with the best encoding accepted by the client. It
+// returns false when the asset has to be served uncompressed.
+func (a *compressedAssets) serve(c *echo.Context, name string) (bool, error) {
+	if !compressibleExts[filepath.Ext(name)] {
+		return false, nil
+	}
+
+	header := c.Response().Header()
+	header.Add("Vary", "Accept-Encoding")
+
+	accepted := acceptedEncodings(c.Request().Header.Get("Accept-Encoding"))
+	for _, encoder := range encoders {
+		if !accepted[encoder.name] {
+			continue
+		}
+
+		b, err := a.variant(name, encoder.name)
+		if err != nil {
+			return false, nil
+		}
+		if b == nil {
+			continue
+		}
+
+		header.Set("Content-Encoding", encoder.name)
+		header.Set("Content-Length", strconv.Itoa(len(b)))
+
+		return true, c.Blob(http.StatusOK, header.Get("Content-Type"), b)
+	}
+
+	return false, nil
+}
+
+// acceptedEncodings parses an Accept-Encoding header, leaving out encodings with q=0.
+func acceptedEncodings(acceptEncoding string) map[string]bool {
+	accepted := map[string]bool{}
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(part, ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if v, err := strconv.ParseFloat(q, 64); err == nil && v == 0 {
+				continue
+			}
+		}
+
+		accepted[name] = true
+	}
+
+	return accepted
+}
@@ -0,0 +1,81 @@
+package echoSwagger
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+	swaggerFiles "github.com/swaggo/files/v2"
+)
+
+func TestCompressAssets(t *testing.T) {
+	var cfg Config
+	CompressAssets(true)(&cfg)
+	assert.True(t, cfg.CompressAssets)
+}
+
+func performEncodedRequest(target, acceptEncoding string, e http.Handler) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodGet, target, nil)
+	r.Header.Set("Accept-Encoding", acceptEncoding)
+	w := httptest.NewRecorder()
+
+	e.ServeHTTP(w, r)
+	return w
+}
+
+func TestCompressedAssets(t *testing.T) {
+	raw, err := fs.ReadFile(swaggerFiles.FS, "swagger-ui-bundle.js")
+	assert.NoError(t, err)
+
+	for _, wrap := range []func(options ...func(*Config)) echo.HandlerFunc{EchoWrapHandler, EchoWrapHandlerV3} {
+		router := echo.New()
+		router.GET("/*", wrap(CompressAssets(true)))
+
+		w1 := performEncodedRequest("/swagger-ui-bundle.js", "gzip, deflate, br", router)
+		assert.Equal(t, http.StatusOK, w1.Code)
+		assert.Equal(t, "br", w1.Header().Get("Content-Encoding"))
+		assert.Equal(t, "Accept-Encoding", w1.Header().Get("Vary"))
+		assert.Equal(t, "application/javascript", w1.Header().Get("Content-Type"))
+		assert.Equal(t, strconv.Itoa(w1.Body.Len()), w1.Header().Get("Content-Length"))
+		assert.Less(t, w1.Body.Len(), len(raw))
+
+		body, err := io.ReadAll(brotli.NewReader(w1.Body))
+		assert.NoError(t, err)
+		assert.Equal(t, raw, body)
+
+		w2 := performEncodedRequest("/swagger-ui-bundle.js", "gzip;q=1.0, br;q=0", router)
+		assert.Equal(t, http.StatusOK, w2.Code)
+		assert.Equal(t, "gzip", w2.Header().Get("Content-Encoding"))
+
+		zr, err := gzip.NewReader(w2.Body)
+		assert.NoError(t, err)
+		body, err = io.ReadAll(zr)
+		assert.NoError(t, err)
+		assert.Equal(t, raw, body)
+
+		w3 := performEncodedRequest("/swagger-ui-bundle.js", "", router)
+		assert.Equal(t, http.StatusOK, w3.Code)
+		assert.Empty(t, w3.Header().Get("Content-Encoding"))
+		assert.Equal(t, "Accept-Encoding", w3.Header().Get("Vary"))
+		assert.True(t, bytes.Equal(raw, w3.Body.Bytes()))
+
+		w4 := performEncodedRequest("/favicon-16x16.png", "gzip, br", router)
+		assert.Equal(t, http.StatusOK, w4.Code)
+		assert.Empty(t, w4.Header().Get("Content-Encoding"))
+
+		assert.Equal(t, http.StatusNotFound, performEncodedRequest("/notfound.js", "gzip", router).Code)
+	}
+}
+
+func TestAcceptedEncodings(t *testing.T) {
+	assert.Equal(t, map[string]bool{"gzip": true, "br": true}, acceptedEncodings("GZIP, br;q=0.5, deflate;q=0"))
+	assert.Empty(t, acceptedEncodings(""))
+}
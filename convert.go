@@ -0,0 +1,564 @@
+package echoSwagger
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+
+	"github.com/labstack/echo/v5"
+)
+
+// openAPIVersion is the OpenAPI version of converted Swagger 2.0 documents.
+const openAPIVersion = "3.0.3"
+
+// openAPI3Doc wraps provider to convert Swagger 2.0 documents to OpenAPI 3.0. Documents of
+// other versions are returned unchanged.
+func openAPI3Doc(provider DocProvider) DocProvider {
+	return DocProviderFunc(func(c *echo.Context, instanceName string) ([]byte, error) {
+		doc, err := provider.ReadDoc(c, instanceName)
+		if err != nil {
+			return nil, err
+		}
+
+		if docVersion(doc) != "2.0" {
+			return doc, nil
+		}
+
+		return convertToOpenAPI3(doc)
+	})
+}
+
+// convertToOpenAPI3 converts a Swagger 2.0 document to OpenAPI 3.0.
+func convertToOpenAPI3(doc []byte) ([]byte, error) {
+	var swagger map[string]any
+	if err := json.Unmarshal(doc, &swagger); err != nil {
+		return nil, err
+	}
+
+	spec := map[string]any{"openapi": openAPIVersion}
+	for key, value := range swagger {
+		switch key {
+		case "info", "tags", "externalDocs", "security":
+			spec[key] = value
+		default:
+			if strings.HasPrefix(key, "x-") {
+				spec[key] = value
+			}
+		}
+	}
+
+	spec["servers"] = convertServers(swagger)
+
+	consumes := stringSlice(swagger["consumes"])
+	produces := stringSlice(swagger["produces"])
+	sharedParameters, _ := swagger["parameters"].(map[string]any)
+
+	paths := map[string]any{}
+	if swaggerPaths, ok := swagger["paths"].(map[string]any); ok {
+		for p, item := range swaggerPaths {
+			if item, ok := item.(map[string]any); ok {
+				paths[p] = convertPathItem(item, consumes, produces, sharedParameters)
+			}
+		}
+	}
+	spec["paths"] = paths
+
+	components := map[string]any{}
+	if definitions, ok := swagger["definitions"].(map[string]any); ok {
+		schemas := map[string]any{}
+		for name, schema := range definitions {
+			schemas[name] = convertSchema(schema)
+		}
+		components["schemas"] = schemas
+	}
+	if parameters, ok := swagger["parameters"].(map[string]any); ok {
+		converted := map[string]any{}
+		for name, parameter := range parameters {
+			// Body and form parameters become request bodies, which cannot be shared by
+			// reference the same way. The operations referencing them inline them instead,
+			// see resolveBodyParameter.
+			if parameter, ok := parameter.(map[string]any); ok && !isBodyParameter(parameter) {
+				converted[name] = convertParameter(parameter)
+			}
+		}
+		components["parameters"] = converted
+	}
+	if responses, ok := swagger["responses"].(map[string]any); ok {
+		converted := map[string]any{}
+		for name, response := range responses {
+			converted[name] = convertResponse(response, produces)
+		}
+		components["responses"] = converted
+	}
+	if securityDefinitions, ok := swagger["securityDefinitions"].(map[string]any); ok {
+		schemes := map[string]any{}
+		for name, scheme := range securityDefinitions {
+			if scheme, ok := scheme.(map[string]any); ok {
+				schemes[name] = convertSecurityScheme(scheme)
+			}
+		}
+		components["securitySchemes"] = schemes
+	}
+	if len(components) > 0 {
+		spec["components"] = components
+	}
+
+	return json.Marshal(convertRefs(spec))
+}
+
+func convertServers(swagger map[string]any) []any {
+	host, _ := swagger["host"].(string)
+	basePath, _ := swagger["basePath"].(string)
+	if basePath == "" {
+		basePath = "/"
+	}
+
+	if host == "" {
+		return []any{map[string]any{"url": basePath}}
+	}
+
+	schemes := stringSlice(swagger["schemes"])
+	if len(schemes) == 0 {
+		schemes = []string{"https"}
+	}
+
+	servers := make([]any, 0, len(schemes))
+	for _, scheme := range schemes {
+		servers = append(servers, map[string]any{
+			"url": (&url.URL{Scheme: scheme, Host: host, Path: basePath}).String(),
+		})
+	}
+
+	return servers
+}
+
+func convertPathItem(item map[string]any, consumes, produces []string, sharedParameters map[string]any) map[string]any {
+	converted := map[string]any{}
+	for key, value := range item {
+		switch key {
+		case "parameters":
+			parameters, _ := value.([]any)
+			var kept []any
+			for _, parameter := range parameters {
+				if parameter, ok := resolveBodyParameter(parameter, sharedParameters).(map[string]any); ok && !isBodyParameter(parameter) {
+					kept = append(kept, convertParameter(parameter))
+				}
+			}
+			if kept != nil {
+				converted[key] = kept
+			}
+		default:
+			operation, ok := value.(map[string]any)
+			if !ok || !isOperationMethod(key) {
+				converted[key] = value
+				continue
+			}
+
+			// Body and form parameters shared by the path item apply to every operation.
+			if parameters, ok := item["parameters"].([]any); ok {
+				operation = withSharedBodyParameters(operation, parameters, sharedParameters)
+			}
+			converted[key] = convertOperation(operation, consumes, produces, sharedParameters)
+		}
+	}
+
+	return converted
+}
+
+func isOperationMethod(key string) bool {
+	for _, method := range operationMethods {
+		if method == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isBodyParameter(parameter map[string]any) bool {
+	in, _ := parameter["in"].(string)
+	return in == "body" || in == "formData"
+}
+
+// resolveBodyParameter returns the body or form parameter parameter references in
+// #/parameters, which is not converted to a component. Other parameters are returned
+// unchanged.
+func resolveBodyParameter(parameter any, sharedParameters map[string]any) any {
+	object, _ := parameter.(map[string]any)
+	ref, _ := object["$ref"].(string)
+	name, ok := strings.CutPrefix(ref, "#/parameters/")
+	if !ok {
+		return parameter
+	}
+
+	name = strings.ReplaceAll(strings.ReplaceAll(name, "~1", "/"), "~0", "~")
+	if shared, ok := sharedParameters[name].(map[string]any); ok && isBodyParameter(shared) {
+		return shared
+	}
+
+	return parameter
+}
+
+func withSharedBodyParameters(operation map[string]any, shared []any, sharedParameters map[string]any) map[string]any {
+	var body []any
+	for _, parameter := range shared {
+		if parameter, ok := resolveBodyParameter(parameter, sharedParameters).(map[string]any); ok && isBodyParameter(parameter) {
+			body = append(body, parameter)
+		}
+	}
+	if body == nil {
+		return operation
+	}
+
+	copied := make(map[string]any, len(operation))
+	for key, value := range operation {
+		copied[key] = value
+	}
+
+	parameters, _ := operation["parameters"].([]any)
+	copied["parameters"] = append(body, parameters...)
+
+	return copied
+}
+
+func convertOperation(operation map[string]any, consumes, produces []string, sharedParameters map[string]any) map[string]any {
+	if operationConsumes, ok := operation["consumes"]; ok {
+		consumes = stringSlice(operationConsumes)
+	}
+	if operationProduces, ok := operation["produces"]; ok {
+		produces = stringSlice(operationProduces)
+	}
+
+	converted := map[string]any{}
+	var parameters []any
+	var formProperties map[string]any
+	var formRequired []any
+
+	for key, value := range operation {
+		switch key {
+		case "consumes", "produces", "schemes":
+		case "parameters":
+			list, _ := value.([]any)
+			for _, parameter := range list {
+				parameter, ok := resolveBodyParameter(parameter, sharedParameters).(map[string]any)
+				if !ok {
+					continue
+				}
+
+				switch parameter["in"] {
+				case "body":
+					converted["requestBody"] = convertBodyParameter(parameter, consumes)
+				case "formData":
+					if formProperties == nil {
+						formProperties = map[string]any{}
+					}
+					name, _ := parameter["name"].(string)
+					formProperties[name] = parameterSchema(parameter)
+					if required, _ := parameter["required"].(bool); required {
+						formRequired = append(formRequired, name)
+					}
+				default:
+					parameters = append(parameters, convertParameter(parameter))
+				}
+			}
+		case "responses":
+			responses := map[string]any{}
+			if value, ok := value.(map[string]any); ok {
+				for code, response := range value {
+					responses[code] = convertResponse(response, produces)
+				}
+			}
+			converted[key] = responses
+		default:
+			converted[key] = value
+		}
+	}
+
+	if parameters != nil {
+		converted["parameters"] = parameters
+	}
+
+	if formProperties != nil {
+		schema := map[string]any{"type": "object", "properties": formProperties}
+		if formRequired != nil {
+			schema["required"] = formRequired
+		}
+
+		mediaTypes := formMediaTypes(consumes, formProperties)
+		content := make(map[string]any, len(mediaTypes))
+		for _, mediaType := range mediaTypes {
+			content[mediaType] = map[string]any{"schema": schema}
+		}
+		converted["requestBody"] = map[string]any{"content": content}
+	}
+
+	return converted
+}
+
+func convertBodyParameter(parameter map[string]any, consumes []string) map[string]any {
+	if len(consumes) == 0 {
+		consumes = []string{"application/json"}
+	}
+
+	schema := convertSchema(parameter["schema"])
+	content := make(map[string]any, len(consumes))
+	for _, mediaType := range consumes {
+		content[mediaType] = map[string]any{"schema": schema}
+	}
+
+	requestBody := map[string]any{"content": content}
+	for _, key := range []string{"description", "required"} {
+		if value, ok := parameter[key]; ok {
+			requestBody[key] = value
+		}
+	}
+
+	return requestBody
+}
+
+func formMediaTypes(consumes []string, properties map[string]any) []string {
+	var mediaTypes []string
+	for _, mediaType := range consumes {
+		if mediaType == "multipart/form-data" || mediaType == "application/x-www-form-urlencoded" {
+			mediaTypes = append(mediaTypes, mediaType)
+		}
+	}
+	if mediaTypes != nil {
+		return mediaTypes
+	}
+
+	for _, property := range properties {
+		if property, ok := property.(map[string]any); ok && property["format"] == "binary" {
+			return []string{"multipart/form-data"}
+		}
+	}
+
+	return []string{"application/x-www-form-urlencoded"}
+}
+
+// schemaKeys lists the keys of a Swagger 2.0 non-body parameter or header that move into
+// the schema in OpenAPI 3.0.
+var schemaKeys = []string{
+	"type", "format", "items", "default", "maximum", "exclusiveMaximum", "minimum", "exclusiveMinimum",
+	"maxLength", "minLength", "pattern", "maxItems", "minItems", "uniqueItems", "enum", "multipleOf",
+}
+
+func parameterSchema(parameter map[string]any) map[string]any {
+	schema := map[string]any{}
+	for _, key := range schemaKeys {
+		if value, ok := parameter[key]; ok {
+			schema[key] = value
+		}
+	}
+
+	return convertSchema(schema).(map[string]any)
+}
+
+func convertParameter(parameter map[string]any) map[string]any {
+	if _, ok := parameter["$ref"]; ok {
+		return parameter
+	}
+
+	converted := map[string]any{"schema": parameterSchema(parameter)}
+	for key, value := range parameter {
+		switch key {
+		case "collectionFormat":
+			switch value {
+			case "csv":
+				converted["style"], converted["explode"] = "form", false
+			case "ssv":
+				converted["style"] = "spaceDelimited"
+			case "pipes":
+				converted["style"] = "pipeDelimited"
+			case "multi":
+				converted["style"], converted["explode"] = "form", true
+			}
+		case "allowEmptyValue", "description", "in", "name", "required":
+			converted[key] = value
+		default:
+			if strings.HasPrefix(key, "x-") {
+				converted[key] = value
+			}
+		}
+	}
+
+	return converted
+}
+
+func convertResponse(response any, produces []string) any {
+	r, ok := response.(map[string]any)
+	if !ok {
+		return response
+	}
+	if _, ok := r["$ref"]; ok {
+		return r
+	}
+
+	if len(produces) == 0 {
+		produces = []string{"application/json"}
+	}
+
+	content := map[string]any{}
+	if schema, ok := r["schema"]; ok {
+		for _, mediaType := range produces {
+			content[mediaType] = map[string]any{"schema": convertSchema(schema)}
+		}
+	}
+
+	// Swagger 2.0 examples are keyed by media type.
+	if examples, ok := r["examples"].(map[string]any); ok {
+		for mediaType, example := range examples {
+			mediaTypeObject, _ := content[mediaType].(map[string]any)
+			if mediaTypeObject == nil {
+				mediaTypeObject = map[string]any{}
+				content[mediaType] = mediaTypeObject
+			}
+			mediaTypeObject["example"] = example
+		}
+	}
+
+	converted := map[string]any{}
+	if len(content) > 0 {
+		converted["content"] = content
+	}
+
+	for key, value := range r {
+		switch key {
+		case "schema", "examples":
+		case "headers":
+			headers := map[string]any{}
+			if value, ok := value.(map[string]any); ok {
+				for name, header := range value {
+					if header, ok := header.(map[string]any); ok {
+						convertedHeader := map[string]any{"schema": parameterSchema(header)}
+						if description, ok := header["description"]; ok {
+							convertedHeader["description"] = description
+						}
+						headers[name] = convertedHeader
+					}
+				}
+			}
+			converted[key] = headers
+		default:
+			converted[key] = value
+		}
+	}
+
+	return converted
+}
+
+// convertSchema converts the Swagger 2.0 specific parts of a schema.
+func convertSchema(schema any) any {
+	switch s := schema.(type) {
+	case map[string]any:
+		converted := make(map[string]any, len(s))
+		for key, value := range s {
+			switch key {
+			case "x-nullable":
+				converted["nullable"] = value
+			case "discriminator":
+				if propertyName, ok := value.(string); ok {
+					converted[key] = map[string]any{"propertyName": propertyName}
+				} else {
+					converted[key] = value
+				}
+			default:
+				converted[key] = convertSchema(value)
+			}
+		}
+
+		if converted["type"] == "file" {
+			converted["type"] = "string"
+			converted["format"] = "binary"
+		}
+
+		return converted
+	case []any:
+		converted := make([]any, len(s))
+		for i, value := range s {
+			converted[i] = convertSchema(value)
+		}
+
+		return converted
+	}
+
+	return schema
+}
+
+func convertSecurityScheme(scheme map[string]any) map[string]any {
+	converted := map[string]any{}
+	if description, ok := scheme["description"]; ok {
+		converted["description"] = description
+	}
+
+	switch scheme["type"] {
+	case "basic":
+		converted["type"] = "http"
+		converted["scheme"] = "basic"
+	case "apiKey":
+		converted["type"] = "apiKey"
+		converted["name"] = scheme["name"]
+		converted["in"] = scheme["in"]
+	case "oauth2":
+		flow := map[string]any{"scopes": map[string]any{}}
+		if scopes, ok := scheme["scopes"]; ok {
+			flow["scopes"] = scopes
+		}
+		if authorizationURL, ok := scheme["authorizationUrl"]; ok {
+			flow["authorizationUrl"] = authorizationURL
+		}
+		if tokenURL, ok := scheme["tokenUrl"]; ok {
+			flow["tokenUrl"] = tokenURL
+		}
+
+		flows := map[string]string{
+			"implicit":    "implicit",
+			"password":    "password",
+			"application": "clientCredentials",
+			"accessCode":  "authorizationCode",
+		}
+		flowName, _ := scheme["flow"].(string)
+
+		converted["type"] = "oauth2"
+		converted["flows"] = map[string]any{flows[flowName]: flow}
+	default:
+		for key, value := range scheme {
+			converted[key] = value
+		}
+	}
+
+	for key, value := range scheme {
+		if strings.HasPrefix(key, "x-") {
+			converted[key] = value
+		}
+	}
+
+	return converted
+}
+
+// refPrefixes maps the Swagger 2.0 reference prefixes to their OpenAPI 3.0 counterparts.
+var refPrefixes = strings.NewReplacer(
+	"#/definitions/", "#/components/schemas/",
+	"#/parameters/", "#/components/parameters/",
+	"#/responses/", "#/components/responses/",
+)
+
+// convertRefs rewrites every $ref of v to OpenAPI 3.0 locations.
+func convertRefs(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if ref, ok := value.(string); ok && key == "$ref" {
+				v[key] = refPrefixes.Replace(ref)
+				continue
+			}
+			v[key] = convertRefs(value)
+		}
+	case []any:
+		for i, value := range v {
+			v[i] = convertRefs(value)
+		}
+	}
+
+	return v
+}
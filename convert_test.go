@@ -0,0 +1,257 @@
+package echoSwagger
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/swaggo/swag"
+)
+
+func TestConvertToOpenAPI3Option(t *testing.T) {
+	var cfg Config
+	ConvertToOpenAPI3(true)(&cfg)
+	assert.True(t, cfg.ConvertToOpenAPI3)
+}
+
+func convertedSpec(t *testing.T, doc string) map[string]any {
+	converted, err := convertToOpenAPI3([]byte(doc))
+	assert.NoError(t, err)
+
+	var spec map[string]any
+	assert.NoError(t, json.Unmarshal(converted, &spec))
+	return spec
+}
+
+func TestConvertToOpenAPI3(t *testing.T) {
+	spec := convertedSpec(t, (&mockedSwag{}).ReadDoc())
+
+	assert.Equal(t, "3.0.3", spec["openapi"])
+	assert.Equal(t, "Swagger Example API", spec["info"].(map[string]any)["title"])
+	assert.Equal(t, []any{map[string]any{"url": "https://petstore.swagger.io/v2"}}, spec["servers"])
+	assert.NotContains(t, spec, "definitions")
+	assert.NotContains(t, spec, "host")
+
+	schemas := spec["components"].(map[string]any)["schemas"].(map[string]any)
+	assert.Contains(t, schemas, "web.APIError")
+	assert.Contains(t, schemas, "web.Pet")
+
+	paths := spec["paths"].(map[string]any)
+
+	upload := paths["/file/upload"].(map[string]any)["post"].(map[string]any)
+	assert.NotContains(t, upload, "consumes")
+	assert.NotContains(t, upload, "parameters")
+	assert.Equal(t, map[string]any{
+		"content": map[string]any{
+			"multipart/form-data": map[string]any{
+				"schema": map[string]any{
+					"type":     "object",
+					"required": []any{"file"},
+					"properties": map[string]any{
+						"file": map[string]any{"type": "string", "format": "binary"},
+					},
+				},
+			},
+		},
+	}, upload["requestBody"])
+	assert.Equal(t, map[string]any{
+		"description": "We need ID!!",
+		"content": map[string]any{
+			"application/json": map[string]any{
+				"schema": map[string]any{"type": "object", "$ref": "#/components/schemas/web.APIError"},
+			},
+		},
+	}, upload["responses"].(map[string]any)["400"])
+
+	get := paths["/testapi/get-string-by-int/{some_id}"].(map[string]any)["get"].(map[string]any)
+	assert.Equal(t, []any{map[string]any{
+		"name":        "some_id",
+		"in":          "path",
+		"description": "Some ID",
+		"required":    true,
+		"schema":      map[string]any{"type": "int"},
+	}}, get["parameters"])
+	assert.Equal(t, map[string]any{
+		"description": "Some ID",
+		"required":    true,
+		"content": map[string]any{
+			"application/json": map[string]any{
+				"schema": map[string]any{"type": "object", "$ref": "#/components/schemas/web.Pet"},
+			},
+		},
+	}, get["requestBody"])
+}
+
+func TestConvertToOpenAPI3Details(t *testing.T) {
+	spec := convertedSpec(t, `{
+		"swagger": "2.0",
+		"schemes": ["http", "https"],
+		"host": "localhost:1323",
+		"produces": ["application/json", "application/xml"],
+		"x-logo": "logo.png",
+		"paths": {
+			"/pets": {
+				"parameters": [{"name": "X-Tenant", "in": "header", "type": "string"}],
+				"get": {
+					"parameters": [
+						{"name": "tags", "in": "query", "type": "array", "items": {"type": "string"}, "collectionFormat": "multi"},
+						{"$ref": "#/parameters/limit"}
+					],
+					"responses": {
+						"200": {
+							"description": "ok",
+							"headers": {"X-Total": {"type": "integer", "description": "total"}},
+							"examples": {"application/json": [{"name": "rex"}]}
+						},
+						"404": {"$ref": "#/responses/NotFound"}
+					}
+				}
+			}
+		},
+		"parameters": {"limit": {"name": "limit", "in": "query", "type": "integer", "maximum": 100}},
+		"responses": {"NotFound": {"description": "not found"}},
+		"definitions": {"Pet": {"type": "object", "discriminator": "kind", "properties": {"tag": {"type": "string", "x-nullable": true}}}},
+		"securityDefinitions": {
+			"basic": {"type": "basic"},
+			"key": {"type": "apiKey", "name": "X-API-Key", "in": "header"},
+			"oauth": {"type": "oauth2", "flow": "accessCode", "authorizationUrl": "https://idp/authorize", "tokenUrl": "https://idp/token", "scopes": {"read": "Read"}}
+		}
+	}`)
+
+	assert.Equal(t, []any{
+		map[string]any{"url": "http://localhost:1323/"},
+		map[string]any{"url": "https://localhost:1323/"},
+	}, spec["servers"])
+	assert.Equal(t, "logo.png", spec["x-logo"])
+
+	item := spec["paths"].(map[string]any)["/pets"].(map[string]any)
+	assert.Equal(t, []any{map[string]any{"name": "X-Tenant", "in": "header", "schema": map[string]any{"type": "string"}}}, item["parameters"])
+
+	get := item["get"].(map[string]any)
+	assert.Equal(t, []any{
+		map[string]any{"name": "tags", "in": "query", "style": "form", "explode": true, "schema": map[string]any{"type": "array", "items": map[string]any{"type": "string"}}},
+		map[string]any{"$ref": "#/components/parameters/limit"},
+	}, get["parameters"])
+
+	responses := get["responses"].(map[string]any)
+	assert.Equal(t, map[string]any{
+		"description": "ok",
+		"headers":     map[string]any{"X-Total": map[string]any{"description": "total", "schema": map[string]any{"type": "integer"}}},
+		"content":     map[string]any{"application/json": map[string]any{"example": []any{map[string]any{"name": "rex"}}}},
+	}, responses["200"])
+	assert.Equal(t, map[string]any{"$ref": "#/components/responses/NotFound"}, responses["404"])
+
+	components := spec["components"].(map[string]any)
+	assert.Equal(t, map[string]any{"limit": map[string]any{"name": "limit", "in": "query", "schema": map[string]any{"type": "integer", "maximum": float64(100)}}}, components["parameters"])
+	assert.Equal(t, map[string]any{"NotFound": map[string]any{"description": "not found"}}, components["responses"])
+	assert.Equal(t, map[string]any{
+		"type":          "object",
+		"discriminator": map[string]any{"propertyName": "kind"},
+		"properties":    map[string]any{"tag": map[string]any{"type": "string", "nullable": true}},
+	}, components["schemas"].(map[string]any)["Pet"])
+	assert.Equal(t, map[string]any{
+		"basic": map[string]any{"type": "http", "scheme": "basic"},
+		"key":   map[string]any{"type": "apiKey", "name": "X-API-Key", "in": "header"},
+		"oauth": map[string]any{"type": "oauth2", "flows": map[string]any{"authorizationCode": map[string]any{
+			"authorizationUrl": "https://idp/authorize",
+			"tokenUrl":         "https://idp/token",
+			"scopes":           map[string]any{"read": "Read"},
+		}}},
+	}, components["securitySchemes"])
+}
+
+func TestConvertToOpenAPI3SharedBodyParameters(t *testing.T) {
+	spec := convertedSpec(t, `{
+		"swagger": "2.0",
+		"paths": {
+			"/pets": {
+				"post": {
+					"consumes": ["application/json"],
+					"parameters": [{"$ref": "#/parameters/pet"}, {"$ref": "#/parameters/limit"}],
+					"responses": {"201": {"description": "created"}}
+				},
+				"put": {
+					"parameters": [{"$ref": "#/parameters/name"}, {"$ref": "#/parameters/photo"}],
+					"responses": {"200": {"description": "ok"}}
+				}
+			},
+			"/pets/{id}": {
+				"parameters": [{"$ref": "#/parameters/pet"}],
+				"patch": {"responses": {"200": {"description": "ok"}}}
+			}
+		},
+		"parameters": {
+			"limit": {"name": "limit", "in": "query", "type": "integer"},
+			"pet": {"name": "pet", "in": "body", "required": true, "schema": {"$ref": "#/definitions/Pet"}},
+			"name": {"name": "name", "in": "formData", "required": true, "type": "string"},
+			"photo": {"name": "photo", "in": "formData", "type": "file"}
+		},
+		"definitions": {"Pet": {"type": "object"}}
+	}`)
+
+	petBody := map[string]any{
+		"required": true,
+		"content":  map[string]any{"application/json": map[string]any{"schema": map[string]any{"$ref": "#/components/schemas/Pet"}}},
+	}
+
+	paths := spec["paths"].(map[string]any)
+	post := paths["/pets"].(map[string]any)["post"].(map[string]any)
+	assert.Equal(t, petBody, post["requestBody"])
+	assert.Equal(t, []any{map[string]any{"$ref": "#/components/parameters/limit"}}, post["parameters"])
+
+	put := paths["/pets"].(map[string]any)["put"].(map[string]any)
+	assert.Nil(t, put["parameters"])
+	assert.Equal(t, map[string]any{"content": map[string]any{"multipart/form-data": map[string]any{"schema": map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"name":  map[string]any{"type": "string"},
+			"photo": map[string]any{"type": "string", "format": "binary"},
+		},
+		"required": []any{"name"},
+	}}}}, put["requestBody"])
+
+	item := paths["/pets/{id}"].(map[string]any)
+	assert.Nil(t, item["parameters"])
+	assert.Equal(t, petBody, item["patch"].(map[string]any)["requestBody"])
+
+	assert.Equal(t, map[string]any{"limit": map[string]any{"name": "limit", "in": "query", "schema": map[string]any{"type": "integer"}}},
+		spec["components"].(map[string]any)["parameters"])
+	converted, err := json.Marshal(spec)
+	assert.NoError(t, err)
+	for _, name := range []string{"pet", "name", "photo"} {
+		assert.NotContains(t, string(converted), `"#/components/parameters/`+name+`"`)
+	}
+}
+
+func TestConvertToOpenAPI3Handler(t *testing.T) {
+	swag.Register("convert", &mockedSwag{})
+
+	router := echo.New()
+	router.GET("/swagger/*", EchoWrapHandler(ConvertToOpenAPI3(true), InstanceName("convert"), InstanceNames("convert")))
+
+	w1 := performRequest(http.MethodGet, "/swagger/openapi.json", router)
+	assert.Equal(t, http.StatusOK, w1.Code)
+	assert.Equal(t, "application/json; charset=utf-8", w1.Header().Get("Content-Type"))
+	assert.Contains(t, w1.Body.String(), `"openapi":"3.0.3"`)
+	assert.NotEmpty(t, w1.Header().Get("ETag"))
+
+	w2 := performRequest(http.MethodGet, "/swagger/convert/openapi.yaml", router)
+	assert.Equal(t, http.StatusOK, w2.Code)
+	assert.Contains(t, w2.Body.String(), "openapi: 3.0.3\n")
+	assert.Contains(t, w2.Body.String(), "$ref: '#/components/schemas/web.APIError'")
+
+	w3 := performRequest(http.MethodGet, "/swagger/doc.json", router)
+	assert.Equal(t, (&mockedSwag{}).ReadDoc(), w3.Body.String())
+
+	// OpenAPI 3 documents are served unchanged.
+	router = echo.New()
+	router.GET("/swagger/*", EchoWrapHandlerV3(ConvertToOpenAPI3(true), Provider(StaticDoc([]byte((&mockedSwag31{}).ReadDoc())))))
+	assert.Equal(t, (&mockedSwag31{}).ReadDoc(), performRequest(http.MethodGet, "/swagger/openapi.json", router).Body.String())
+
+	// The conversion is disabled by default.
+	router = echo.New()
+	router.GET("/swagger/*", EchoWrapHandler(InstanceName("convert")))
+	assert.Equal(t, http.StatusNotFound, performRequest(http.MethodGet, "/swagger/openapi.json", router).Code)
+}
@@ -0,0 +1,314 @@
+package echoSwagger
+
+import (
+	"encoding/json"
+	"path"
+	"strings"
+
+	"github.com/labstack/echo/v5"
+)
+
+// Operation describes an operation of the API definition, as seen by an OperationFilter.
+type Operation struct {
+	// The path of the operation, e.g. `/pets/{id}`.
+	Path string
+
+	// The HTTP method of the operation in upper case.
+	Method string
+
+	Tags []string
+
+	// The security requirements of the operation, by security scheme name. Operations
+	// without security requirements inherit the global ones.
+	Security []map[string][]string
+}
+
+// OperationFilter reports whether an operation is kept in the served API definition.
+type OperationFilter func(op Operation) bool
+
+// IncludeTags keeps the operations having at least one of the given tags.
+func IncludeTags(tags ...string) OperationFilter {
+	return func(op Operation) bool {
+		return hasAnyTag(op, tags)
+	}
+}
+
+// ExcludeTags keeps the operations having none of the given tags.
+func ExcludeTags(tags ...string) OperationFilter {
+	return func(op Operation) bool {
+		return !hasAnyTag(op, tags)
+	}
+}
+
+// IncludePaths keeps the operations whose path matches one of the given patterns. See
+// path.Match for the pattern syntax.
+func IncludePaths(patterns ...string) OperationFilter {
+	return func(op Operation) bool {
+		for _, pattern := range patterns {
+			if ok, _ := path.Match(pattern, op.Path); ok {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// RequireScopes keeps the operations that can be called with the given OAuth2 scopes,
+// including the ones without security requirements.
+func RequireScopes(scopes ...string) OperationFilter {
+	granted := make(map[string]bool, len(scopes))
+	for _, scope := range scopes {
+		granted[scope] = true
+	}
+
+	return func(op Operation) bool {
+		if len(op.Security) == 0 {
+			return true
+		}
+
+	requirements:
+		for _, requirement := range op.Security {
+			for _, required := range requirement {
+				for _, scope := range required {
+					if !granted[scope] {
+						continue requirements
+					}
+				}
+			}
+
+			return true
+		}
+
+		return false
+	}
+}
+
+func hasAnyTag(op Operation, tags []string) bool {
+	for _, tag := range op.Tags {
+		for _, t := range tags {
+			if tag == t {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// operationFilter returns the filter applied to the API definition served for c, or nil
+// if the definition is served unfiltered.
+func (config *Config) operationFilter(c *echo.Context) OperationFilter {
+	if config.RequestFilter == nil {
+		return config.Filter
+	}
+
+	filter, requestFilter := config.Filter, config.RequestFilter(c)
+	switch {
+	case filter == nil:
+		return requestFilter
+	case requestFilter == nil:
+		return filter
+	}
+
+	return func(op Operation) bool {
+		return filter(op) && requestFilter(op)
+	}
+}
+
+// operationMethods lists the keys of a path item holding an operation.
+var operationMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// filteredDoc wraps provider to prune the operations rejected by the filter of the request.
+func filteredDoc(provider DocProvider, config *Config) DocProvider {
+	return DocProviderFunc(func(c *echo.Context, instanceName string) ([]byte, error) {
+		doc, err := provider.ReadDoc(c, instanceName)
+		if err != nil {
+			return nil, err
+		}
+
+		keep := config.operationFilter(c)
+		if keep == nil {
+			return doc, nil
+		}
+
+		return filterDoc(doc, keep)
+	})
+}
+
+// filterDoc removes the operations rejected by keep from doc, along with the definitions,
+// components and tags no longer referenced.
+func filterDoc(doc []byte, keep OperationFilter) ([]byte, error) {
+	var spec map[string]any
+	if err := json.Unmarshal(doc, &spec); err != nil {
+		return nil, err
+	}
+
+	globalSecurity := securityRequirements(spec["security"])
+	usedTags := map[string]bool{}
+
+	paths, _ := spec["paths"].(map[string]any)
+	for p, item := range paths {
+		item, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		operations := 0
+		for _, method := range operationMethods {
+			operation, ok := item[method].(map[string]any)
+			if !ok {
+				continue
+			}
+
+			op := Operation{
+				Path:     p,
+				Method:   strings.ToUpper(method),
+				Tags:     stringSlice(operation["tags"]),
+				Security: globalSecurity,
+			}
+			if security, ok := operation["security"]; ok {
+				op.Security = securityRequirements(security)
+			}
+
+			if !keep(op) {
+				delete(item, method)
+				continue
+			}
+
+			operations++
+			for _, tag := range op.Tags {
+				usedTags[tag] = true
+			}
+		}
+
+		if operations == 0 {
+			delete(paths, p)
+		}
+	}
+
+	if tags, ok := spec["tags"].([]any); ok {
+		kept := []any{}
+		for _, tag := range tags {
+			if tag, ok := tag.(map[string]any); ok {
+				if name, _ := tag["name"].(string); !usedTags[name] {
+					continue
+				}
+			}
+			kept = append(kept, tag)
+		}
+		spec["tags"] = kept
+	}
+
+	pruneUnreferenced(spec)
+
+	return json.Marshal(spec)
+}
+
+// pruneUnreferenced removes the reusable definitions not referenced from the rest of the
+// API definition, directly or through other definitions.
+func pruneUnreferenced(spec map[string]any) {
+	pools := map[string]map[string]any{}
+	addPool := func(prefix string, pool any) {
+		if pool, ok := pool.(map[string]any); ok {
+			pools[prefix] = pool
+		}
+	}
+
+	addPool("#/definitions/", spec["definitions"])
+	addPool("#/parameters/", spec["parameters"])
+	addPool("#/responses/", spec["responses"])
+	if components, ok := spec["components"].(map[string]any); ok {
+		for kind, pool := range components {
+			// Security schemes are referenced by name rather than by $ref.
+			if kind != "securitySchemes" {
+				addPool("#/components/"+kind+"/", pool)
+			}
+		}
+	}
+
+	reachable := map[string]bool{}
+	var visit func(v any)
+	visit = func(v any) {
+		switch v := v.(type) {
+		case map[string]any:
+			if ref, ok := v["$ref"].(string); ok && !reachable[ref] {
+				reachable[ref] = true
+				for prefix, pool := range pools {
+					if name, ok := strings.CutPrefix(ref, prefix); ok {
+						visit(pool[unescapePointer(name)])
+					}
+				}
+			}
+			for _, child := range v {
+				visit(child)
+			}
+		case []any:
+			for _, child := range v {
+				visit(child)
+			}
+		}
+	}
+
+	for key, value := range spec {
+		if key == "definitions" || key == "parameters" || key == "responses" {
+			continue
+		}
+		if key == "components" {
+			if components, ok := value.(map[string]any); ok {
+				visit(components["securitySchemes"])
+			}
+			continue
+		}
+		visit(value)
+	}
+
+	for prefix, pool := range pools {
+		for name := range pool {
+			if !reachable[prefix+escapePointer(name)] {
+				delete(pool, name)
+			}
+		}
+	}
+}
+
+func escapePointer(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
+
+func unescapePointer(s string) string {
+	return strings.NewReplacer("~1", "/", "~0", "~").Replace(s)
+}
+
+func stringSlice(v any) []string {
+	values, _ := v.([]any)
+
+	s := make([]string, 0, len(values))
+	for _, value := range values {
+		if value, ok := value.(string); ok {
+			s = append(s, value)
+		}
+	}
+
+	return s
+}
+
+func securityRequirements(v any) []map[string][]string {
+	values, _ := v.([]any)
+
+	requirements := make([]map[string][]string, 0, len(values))
+	for _, value := range values {
+		value, ok := value.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		requirement := make(map[string][]string, len(value))
+		for name, scopes := range value {
+			requirement[name] = stringSlice(scopes)
+		}
+		requirements = append(requirements, requirement)
+	}
+
+	return requirements
+}
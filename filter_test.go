@@ -0,0 +1,156 @@
+package echoSwagger
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+const filterDocJSON = `{
+    "swagger": "2.0",
+    "tags": [{"name": "public"}, {"name": "internal"}],
+    "security": [{"oauth2": ["read"]}],
+    "paths": {
+        "/pets": {
+            "get": {
+                "tags": ["public"],
+                "responses": {"200": {"description": "ok", "schema": {"$ref": "#/definitions/web.Pets"}}}
+            },
+            "post": {
+                "tags": ["internal"],
+                "security": [{"oauth2": ["write"]}],
+                "responses": {"200": {"description": "ok", "schema": {"$ref": "#/definitions/web.Pet"}}}
+            }
+        },
+        "/admin/users": {
+            "get": {
+                "tags": ["internal"],
+                "responses": {"200": {"description": "ok", "schema": {"$ref": "#/definitions/web.User"}}}
+            }
+        }
+    },
+    "definitions": {
+        "web.Pets": {"type": "array", "items": {"$ref": "#/definitions/web.Pet"}},
+        "web.Pet": {"type": "object"},
+        "web.User": {"type": "object"}
+    }
+}`
+
+func filteredSpec(t *testing.T, keep OperationFilter) map[string]any {
+	doc, err := filterDoc([]byte(filterDocJSON), keep)
+	assert.NoError(t, err)
+
+	var spec map[string]any
+	assert.NoError(t, json.Unmarshal(doc, &spec))
+	return spec
+}
+
+func TestFilterOptions(t *testing.T) {
+	var cfg Config
+	Filter(IncludeTags("public"))(&cfg)
+	assert.NotNil(t, cfg.Filter)
+
+	RequestFilter(func(*echo.Context) OperationFilter { return nil })(&cfg)
+	assert.NotNil(t, cfg.RequestFilter)
+}
+
+func TestIncludeTags(t *testing.T) {
+	spec := filteredSpec(t, IncludeTags("public"))
+	assert.Equal(t, map[string]any{
+		"/pets": map[string]any{
+			"get": map[string]any{
+				"tags":      []any{"public"},
+				"responses": map[string]any{"200": map[string]any{"description": "ok", "schema": map[string]any{"$ref": "#/definitions/web.Pets"}}},
+			},
+		},
+	}, spec["paths"])
+	assert.Equal(t, []any{map[string]any{"name": "public"}}, spec["tags"])
+	assert.Equal(t, map[string]any{
+		"web.Pets": map[string]any{"type": "array", "items": map[string]any{"$ref": "#/definitions/web.Pet"}},
+		"web.Pet":  map[string]any{"type": "object"},
+	}, spec["definitions"])
+}
+
+func TestExcludeTags(t *testing.T) {
+	spec := filteredSpec(t, ExcludeTags("public"))
+	assert.Len(t, spec["paths"], 2)
+	assert.NotContains(t, spec["paths"].(map[string]any)["/pets"], "get")
+	assert.Len(t, spec["definitions"], 2)
+	assert.NotContains(t, spec["definitions"], "web.Pets")
+}
+
+func TestIncludePaths(t *testing.T) {
+	spec := filteredSpec(t, IncludePaths("/admin/*"))
+	assert.Len(t, spec["paths"], 1)
+	assert.Contains(t, spec["paths"], "/admin/users")
+	assert.Equal(t, map[string]any{"web.User": map[string]any{"type": "object"}}, spec["definitions"])
+}
+
+func TestRequireScopes(t *testing.T) {
+	spec := filteredSpec(t, RequireScopes("read"))
+	assert.Len(t, spec["paths"], 2)
+	assert.NotContains(t, spec["paths"].(map[string]any)["/pets"], "post")
+
+	spec = filteredSpec(t, RequireScopes("read", "write"))
+	assert.Contains(t, spec["paths"].(map[string]any)["/pets"], "post")
+}
+
+func TestPruneUnreferencedComponents(t *testing.T) {
+	doc := `{
+		"openapi": "3.0.3",
+		"paths": {"/pets": {"get": {"responses": {"200": {"$ref": "#/components/responses/Pets"}}}}},
+		"components": {
+			"responses": {"Pets": {"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet~1v2"}}}}},
+			"schemas": {"Pet/v2": {"type": "object"}, "User": {"type": "object"}},
+			"securitySchemes": {"oauth2": {"type": "oauth2"}}
+		}
+	}`
+
+	filtered, err := filterDoc([]byte(doc), func(Operation) bool { return true })
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"openapi": "3.0.3",
+		"paths": {"/pets": {"get": {"responses": {"200": {"$ref": "#/components/responses/Pets"}}}}},
+		"components": {
+			"responses": {"Pets": {"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet~1v2"}}}}},
+			"schemas": {"Pet/v2": {"type": "object"}},
+			"securitySchemes": {"oauth2": {"type": "oauth2"}}
+		}
+	}`, string(filtered))
+}
+
+func TestFilterHandler(t *testing.T) {
+	for _, wrap := range []func(options ...func(*Config)) echo.HandlerFunc{EchoWrapHandler, EchoWrapHandlerV3} {
+		router := echo.New()
+		router.GET("/*", wrap(
+			Provider(StaticDoc([]byte(filterDocJSON))),
+			Filter(ExcludeTags("deprecated")),
+			RequestFilter(func(c *echo.Context) OperationFilter {
+				if c.Request().Header.Get("X-Staff") == "true" {
+					return nil
+				}
+				return IncludeTags("public")
+			}),
+		))
+
+		w1 := performRequest(http.MethodGet, "/doc.json", router)
+		assert.Equal(t, http.StatusOK, w1.Code)
+		assert.NotContains(t, w1.Body.String(), "/admin/users")
+		assert.NotContains(t, w1.Body.String(), "web.User")
+
+		w2 := performRequest(http.MethodGet, "/doc.yaml", router)
+		assert.Equal(t, http.StatusOK, w2.Code)
+		assert.NotContains(t, w2.Body.String(), "/admin/users")
+
+		r := httptest.NewRequest(http.MethodGet, "/doc.json", nil)
+		r.Header.Set("X-Staff", "true")
+		w3 := httptest.NewRecorder()
+		router.ServeHTTP(w3, r)
+		assert.Equal(t, http.StatusOK, w3.Code)
+		assert.Contains(t, w3.Body.String(), "/admin/users")
+	}
+}
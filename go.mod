@@ -3,6 +3,7 @@ module github.com/swaggo/echo-swagger/v2
 go 1.25.0
 
 require (
+	github.com/andybalholm/brotli v1.2.6
 	github.com/labstack/echo/v5 v5.0.0
 	github.com/stretchr/testify v1.11.1
 	github.com/swaggo/files/v2 v2.0.0
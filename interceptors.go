@@ -0,0 +1,171 @@
+package echoSwagger
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v5"
+)
+
+// interceptorsScript is the script defining the interceptors of Swagger UI, served next to
+// index.html rather than inlined so that it complies with a strict Content-Security-Policy.
+const interceptorsScript = "interceptors.js"
+
+// InterceptorConfig stores the request and response interceptors of Swagger UI, applied to
+// the requests it makes, including "Try it out".
+type InterceptorConfig struct {
+	// Headers added to the requests to the origin of the page and to the hosts of the API
+	// definitions, so that they are not sent to third parties such as OAuth2 providers.
+	Headers map[string]string
+
+	// Headers added to the same requests as Headers from the cookies of the page, by header
+	// name, e.g. `"X-CSRF-Token": "csrf_token"`. HttpOnly cookies cannot be read.
+	CookieHeaders map[string]string
+
+	// JavaScript appended to interceptors.js, e.g. to define the functions named by
+	// RequestInterceptor and ResponseInterceptor.
+	Script string
+
+	// The name of the global function passed every request, after Headers and
+	// CookieHeaders are added. It returns the request or a promise of it.
+	RequestInterceptor string
+
+	// The name of the global function passed every response. It returns the response or
+	// a promise of it.
+	ResponseInterceptor string
+}
+
+const interceptorsTemplate = `window.echoSwaggerInterceptors = (function () {
+  var headers = %s;
+  var cookieHeaders = %s;
+  var requestInterceptor = %s;
+  var responseInterceptor = %s;
+  var apiHosts = %s;
+  var proxy = %t;
+  var proxyHosts = %s;
+  var base = document.currentScript ? document.currentScript.src : window.location.href;
+
+  function cookie(name) {
+    var cookies = document.cookie ? document.cookie.split("; ") : [];
+    for (var i = 0; i < cookies.length; i++) {
+      var separator = cookies[i].indexOf("=");
+      if (cookies[i].slice(0, separator) === name) {
+        return decodeURIComponent(cookies[i].slice(separator + 1));
+      }
+    }
+  }
+
+  // OAuth2 token requests carry the client secret and the authorization code, which must
+  // only be sent to the authorization server.
+  function tokenRequest(request) {
+    return typeof request.body === "string" && /(^|&)grant_type=/.test(request.body);
+  }
+
+  return {
+    request: function (request) {
+      var url = new URL(request.url, window.location.href);
+      var sameOrigin = url.origin === window.location.origin;
+
+      if (sameOrigin || apiHosts.indexOf(url.host) >= 0) {
+        Object.keys(headers).forEach(function (name) {
+          request.headers[name] = headers[name];
+        });
+        Object.keys(cookieHeaders).forEach(function (name) {
+          var value = cookie(cookieHeaders[name]);
+          if (value !== undefined) {
+            request.headers[name] = value;
+          }
+        });
+      }
+
+      if (proxy && !sameOrigin && proxyHosts.indexOf(url.host) >= 0 && !tokenRequest(request) &&
+          (url.protocol === "http:" || url.protocol === "https:")) {
+        request.url = new URL("%s" + url.protocol.slice(0, -1) + "/" + url.host + url.pathname + url.search, base).href;
+      }
+
+      return window[requestInterceptor] ? window[requestInterceptor](request) : request;
+    },
+    response: function (response) {
+      return window[responseInterceptor] ? window[responseInterceptor](response) : response;
+    }
+  };
+})();
+`
+
+// newInterceptorsScript returns interceptors.js, or nil if neither Interceptors nor
+// TryItOutProxy is set. Headers are only added to the requests to the origin of the page
+// and to apiHosts. With TryItOutProxy, the requests to proxyHosts but OAuth2 token requests
+// are sent through the proxy.
+func newInterceptorsScript(c *Config, apiHosts, proxyHosts []string) ([]byte, error) {
+	if c.Interceptors == nil && c.TryItOutProxy == nil {
+		return nil, nil
+	}
+
+	config := c.Interceptors
+	if config == nil {
+		config = &InterceptorConfig{}
+	}
+
+	var values [6][]byte
+	for i, v := range []any{
+		nonNilMap(config.Headers),
+		nonNilMap(config.CookieHeaders),
+		config.RequestInterceptor,
+		config.ResponseInterceptor,
+		lowerHosts(apiHosts),
+		lowerHosts(proxyHosts),
+	} {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = b
+	}
+
+	script := fmt.Sprintf(interceptorsTemplate, values[0], values[1], values[2], values[3], values[4], c.TryItOutProxy != nil, values[5], proxyDir)
+	if config.Script != "" {
+		script += "\n" + config.Script + "\n"
+	}
+
+	return []byte(script), nil
+}
+
+func nonNilMap(m map[string]string) map[string]string {
+	if m == nil {
+		return map[string]string{}
+	}
+
+	return m
+}
+
+// lowerHosts returns hosts in lower case, the way URL.host reads in the browser.
+func lowerHosts(hosts []string) []string {
+	lower := make([]string, len(hosts))
+	for i, host := range hosts {
+		lower[i] = strings.ToLower(host)
+	}
+
+	return lower
+}
+
+// serveInterceptors serves interceptors.js, for the hosts of the API definitions as
+// provided, before DynamicHost rewrites them from the request.
+func (h *swaggerHandler) serveInterceptors(c *echo.Context) error {
+	apiHosts := h.specHosts(c)
+	var proxyHosts []string
+	if h.proxy != nil {
+		proxyHosts = h.proxy.hosts(apiHosts)
+		apiHosts = append(apiHosts, proxyHosts...)
+	}
+
+	script, err := newInterceptorsScript(h.config, apiHosts, proxyHosts)
+	if err != nil {
+		return err
+	}
+
+	c.Response().Header().Set("Cache-Control", "no-cache")
+
+	return c.Blob(http.StatusOK, "application/javascript", script)
+}
@@ -0,0 +1,74 @@
+package echoSwagger
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterceptorsOption(t *testing.T) {
+	var cfg Config
+	expected := &InterceptorConfig{Headers: map[string]string{"X-Tenant": "acme"}}
+	Interceptors(expected)(&cfg)
+	assert.Equal(t, expected, cfg.Interceptors)
+}
+
+func TestNewInterceptorsScript(t *testing.T) {
+	script, err := newInterceptorsScript(&Config{}, nil, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, script)
+
+	script, err = newInterceptorsScript(&Config{Interceptors: &InterceptorConfig{
+		Headers:             map[string]string{"X-Tenant": "</script>"},
+		CookieHeaders:       map[string]string{"X-CSRF-Token": "csrf_token"},
+		Script:              "function logResponse(response) { console.log(response.status); return response; }",
+		ResponseInterceptor: "logResponse",
+	}}, []string{"API.example.com"}, nil)
+	assert.NoError(t, err)
+	assert.Contains(t, string(script), `var headers = {"X-Tenant":"\u003c/script\u003e"};`)
+	assert.Contains(t, string(script), `var cookieHeaders = {"X-CSRF-Token":"csrf_token"};`)
+	assert.Contains(t, string(script), `var requestInterceptor = "";`)
+	assert.Contains(t, string(script), `var responseInterceptor = "logResponse";`)
+	assert.Contains(t, string(script), `var apiHosts = ["api.example.com"];`)
+	assert.Contains(t, string(script), "\nfunction logResponse(response) {")
+
+	script, err = newInterceptorsScript(&Config{Interceptors: &InterceptorConfig{}}, nil, nil)
+	assert.NoError(t, err)
+	assert.Contains(t, string(script), `var headers = {};`)
+	assert.Contains(t, string(script), `var apiHosts = [];`)
+	assert.Contains(t, string(script), `var proxy = false;`)
+}
+
+func TestInterceptorsHandler(t *testing.T) {
+	for _, wrap := range []func(options ...func(*Config)) echo.HandlerFunc{EchoWrapHandler, EchoWrapHandlerV3} {
+		router := echo.New()
+		router.GET("/swagger/*", wrap(
+			Interceptors(&InterceptorConfig{Headers: map[string]string{"X-Tenant": "acme"}}),
+			SecurityHeaders(&SecurityHeadersConfig{}),
+			Provider(StaticDoc([]byte(`{"swagger": "2.0", "host": "api.example.com", "paths": {}}`))),
+		))
+
+		w1 := performRequest(http.MethodGet, "/swagger/index.html", router)
+		assert.Equal(t, http.StatusOK, w1.Code)
+		assert.Contains(t, w1.Body.String(), `<script src="./interceptors.js"> </script>`)
+		assert.Contains(t, w1.Body.String(), `requestInterceptor: window.echoSwaggerInterceptors.request,`)
+		assert.Contains(t, w1.Body.String(), `responseInterceptor: window.echoSwaggerInterceptors.response,`)
+
+		w2 := performRequest(http.MethodGet, "/swagger/interceptors.js", router)
+		assert.Equal(t, http.StatusOK, w2.Code)
+		assert.Equal(t, "application/javascript", w2.Header().Get("Content-Type"))
+		assert.Equal(t, "no-cache", w2.Header().Get("Cache-Control"))
+		assert.Contains(t, w2.Body.String(), `var headers = {"X-Tenant":"acme"};`)
+		// Headers are only added to the requests to the page origin and to the API hosts.
+		assert.Contains(t, w2.Body.String(), `var apiHosts = ["api.example.com"];`)
+		assert.Contains(t, w2.Body.String(), `if (sameOrigin || apiHosts.indexOf(url.host) >= 0) {`)
+	}
+
+	router := echo.New()
+	router.GET("/swagger/*", EchoWrapHandler())
+
+	assert.NotContains(t, performRequest(http.MethodGet, "/swagger/index.html", router).Body.String(), "interceptors")
+	assert.Equal(t, http.StatusNotFound, performRequest(http.MethodGet, "/swagger/interceptors.js", router).Code)
+}
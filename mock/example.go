@@ -0,0 +1,150 @@
+package mock
+
+import (
+	"slices"
+	"strings"
+)
+
+// stringExamples are the examples of the string formats, the way Swagger UI shows them.
+var stringExamples = map[string]string{
+	"date":      "2017-07-21",
+	"date-time": "2017-07-21T17:32:28Z",
+	"email":     "user@example.com",
+	"hostname":  "example.com",
+	"ipv4":      "198.51.100.42",
+	"ipv6":      "2001:db8::1",
+	"uri":       "https://example.com/",
+	"url":       "https://example.com/",
+	"uuid":      "3fa85f64-5717-4562-b3fc-2c963f66afa6",
+}
+
+// resolve follows the local reference of value, e.g. {"$ref": "#/definitions/Pet"}.
+// Other values, and references that cannot be followed, are returned unchanged.
+func (s *spec) resolve(value any) any {
+	// Chains of references are followed up to a limit, so that cyclic ones end.
+	for range 32 {
+		object, _ := value.(map[string]any)
+		ref, _ := object["$ref"].(string)
+		pointer, ok := strings.CutPrefix(ref, "#/")
+		if !ok {
+			return value
+		}
+
+		var target any = s.doc
+		for _, token := range strings.Split(pointer, "/") {
+			token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+			parent, _ := target.(map[string]any)
+			if target, ok = parent[token]; !ok {
+				return value
+			}
+		}
+		value = target
+	}
+
+	return value
+}
+
+// example returns the example of schema: its example, default or first enum value, or
+// one synthesized from its type. refs are the references followed so far, so that
+// recursive schemas end.
+func (s *spec) example(value any, refs []string) any {
+	schema, _ := value.(map[string]any)
+	if ref, ok := schema["$ref"].(string); ok {
+		if slices.Contains(refs, ref) {
+			return nil
+		}
+		schema, _ = s.resolve(schema).(map[string]any)
+		refs = append(refs, ref)
+	}
+
+	if example, ok := schema["example"]; ok {
+		return example
+	}
+	// OpenAPI 3.1 schemas have a list of examples instead.
+	if examples, ok := schema["examples"].([]any); ok && len(examples) > 0 {
+		return examples[0]
+	}
+	if value, ok := schema["default"]; ok {
+		return value
+	}
+	if enum, ok := schema["enum"].([]any); ok && len(enum) > 0 {
+		return enum[0]
+	}
+
+	if allOf, ok := schema["allOf"].([]any); ok {
+		merged := map[string]any{}
+		for _, subschema := range allOf {
+			object, ok := s.example(subschema, refs).(map[string]any)
+			if !ok {
+				continue
+			}
+			for name, value := range object {
+				merged[name] = value
+			}
+		}
+		return merged
+	}
+	for _, keyword := range []string{"oneOf", "anyOf"} {
+		if subschemas, ok := schema[keyword].([]any); ok && len(subschemas) > 0 {
+			return s.example(subschemas[0], refs)
+		}
+	}
+
+	switch schemaType(schema) {
+	case "object":
+		object := map[string]any{}
+		properties, _ := schema["properties"].(map[string]any)
+		for name, property := range properties {
+			if value := s.example(property, refs); value != nil {
+				object[name] = value
+			}
+		}
+		if additionalProperties, ok := schema["additionalProperties"].(map[string]any); ok && len(properties) == 0 {
+			if value := s.example(additionalProperties, refs); value != nil {
+				object["additionalProp1"] = value
+			}
+		}
+		return object
+	case "array":
+		if value := s.example(schema["items"], refs); value != nil {
+			return []any{value}
+		}
+		return []any{}
+	case "string":
+		format, _ := schema["format"].(string)
+		if example, ok := stringExamples[format]; ok {
+			return example
+		}
+		return "string"
+	case "integer", "number":
+		if minimum, ok := schema["minimum"].(float64); ok {
+			return minimum
+		}
+		return 0
+	case "boolean":
+		return true
+	}
+
+	return nil
+}
+
+// schemaType returns the type of schema, the first one but null for an OpenAPI 3.1 list of
+// types. A schema with properties is an object.
+func schemaType(schema map[string]any) string {
+	switch t := schema["type"].(type) {
+	case string:
+		return t
+	case []any:
+		for _, t := range t {
+			if t, ok := t.(string); ok && t != "null" {
+				return t
+			}
+		}
+	}
+
+	if _, ok := schema["properties"]; ok {
+		return "object"
+	}
+
+	return ""
+}
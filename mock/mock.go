@@ -0,0 +1,551 @@
+// Package mock serves example responses for the operations of an API definition, so that
+// clients can be developed before the API is implemented.
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/labstack/echo/v5"
+	echoSwagger "github.com/swaggo/echo-swagger/v2"
+	"github.com/swaggo/swag"
+)
+
+// Config stores mock configuration variables.
+type Config struct {
+	// The swag instance the API definition is read from. Default is swag.Name.
+	InstanceName string
+
+	// The provider of the API definition. Default is echoSwagger.SwagRegistry; use
+	// echoSwagger.SwagV2Registry for the definitions served by EchoWrapHandlerV3. It is
+	// called once, by Register, with the context of a synthetic `GET /` request.
+	DocProvider echoSwagger.DocProvider
+
+	// The path the operation paths are relative to, "/" for none. Default is the basePath
+	// of a Swagger 2.0 definition, the path of the first server url of an OpenAPI 3 one.
+	BasePath string
+}
+
+// InstanceName sets the swag instance the API definition is read from.
+func InstanceName(name string) func(*Config) {
+	return func(c *Config) {
+		c.InstanceName = name
+	}
+}
+
+// Provider sets the provider of the API definition.
+func Provider(provider echoSwagger.DocProvider) func(*Config) {
+	return func(c *Config) {
+		c.DocProvider = provider
+	}
+}
+
+// BasePath sets the path the operation paths are relative to.
+func BasePath(basePath string) func(*Config) {
+	return func(c *Config) {
+		c.BasePath = basePath
+	}
+}
+
+func newConfig(options ...func(*Config)) *Config {
+	config := &Config{
+		InstanceName: swag.Name,
+		DocProvider:  echoSwagger.SwagRegistry,
+	}
+
+	for _, opt := range options {
+		opt(config)
+	}
+
+	return config
+}
+
+// Router registers the mock routes. It is implemented by *echo.Echo and *echo.Group.
+type Router interface {
+	AddRoute(route echo.Route) (echo.RouteInfo, error)
+}
+
+// methods are the HTTP methods of the operations of a path item.
+var methods = []string{
+	http.MethodGet,
+	http.MethodPut,
+	http.MethodPost,
+	http.MethodDelete,
+	http.MethodOptions,
+	http.MethodHead,
+	http.MethodPatch,
+	http.MethodTrace,
+}
+
+// pathParamPattern matches the path parameters of an operation path, e.g. {id}.
+var pathParamPattern = regexp.MustCompile(`\{([^}]+)\}`)
+
+// Register registers on router a route for every operation of the API definition. The
+// route responds with the example of the first documented 2xx response, or of the status
+// requested by the `Prefer: code=404` header. The `Prefer: example=name` header selects a
+// named example of an OpenAPI 3 definition, and the Accept header the media type.
+//
+// Examples are read from the example and examples fields of the responses and schemas,
+// or synthesized from the schemas.
+func Register(router Router, options ...func(*Config)) error {
+	config := newConfig(options...)
+
+	// Providers reading the request, e.g. its host, get a synthetic one.
+	r := &http.Request{
+		Method:     http.MethodGet,
+		URL:        &url.URL{Path: "/"},
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{},
+		Body:       http.NoBody,
+		RequestURI: "/",
+	}
+	c := echo.NewContext(r, &discardResponse{header: http.Header{}})
+	doc, err := config.DocProvider.ReadDoc(c, config.InstanceName)
+	if err != nil {
+		return fmt.Errorf("mock: reading the API definition of %q: %w", config.InstanceName, err)
+	}
+
+	spec, err := newSpec(doc)
+	if err != nil {
+		return err
+	}
+
+	basePath := config.BasePath
+	if basePath == "" {
+		basePath = spec.basePath()
+	}
+	basePath = strings.TrimSuffix(basePath, "/")
+
+	paths, _ := spec.doc["paths"].(map[string]any)
+	for _, path := range sortedKeys(paths) {
+		item, _ := spec.resolve(paths[path]).(map[string]any)
+		for _, method := range methods {
+			op, ok := item[strings.ToLower(method)].(map[string]any)
+			if !ok {
+				continue
+			}
+
+			o, err := spec.operation(op)
+			if err != nil {
+				return fmt.Errorf("mock: %s %s: %w", method, path, err)
+			}
+
+			if _, err := router.AddRoute(echo.Route{
+				Method:  method,
+				Path:    basePath + pathParamPattern.ReplaceAllString(path, ":$1"),
+				Handler: o.serve,
+			}); err != nil {
+				return fmt.Errorf("mock: %s %s: %w", method, path, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// discardResponse is the response of the synthetic request the API definition is read
+// with, which is never sent.
+type discardResponse struct {
+	header http.Header
+}
+
+func (w *discardResponse) Header() http.Header {
+	return w.header
+}
+
+func (w *discardResponse) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+func (w *discardResponse) WriteHeader(int) {}
+
+// operation stores the example responses of an operation.
+type operation struct {
+	// The responses by status code, "2XX" style range or "default".
+	responses map[string]*response
+
+	// The key of the response served without a `Prefer: code` header.
+	defaultKey string
+}
+
+// response stores the example payloads of a response by media type, the preferred one first.
+type response struct {
+	contents []*content
+}
+
+type content struct {
+	mediaType string
+
+	// The example payload, nil if the response has no body.
+	body []byte
+
+	// The named examples of an OpenAPI 3 definition.
+	examples map[string][]byte
+}
+
+// serve responds with the example selected by the Prefer and Accept headers.
+func (o *operation) serve(c *echo.Context) error {
+	preferences := parsePrefer(c.Request().Header.Values("Prefer"))
+
+	var applied []string
+
+	key, status := o.defaultKey, statusCode(o.defaultKey)
+	if code, ok := preferences["code"]; ok {
+		var err error
+		if key, status, err = o.selectResponse(code); err != nil {
+			return c.String(http.StatusBadRequest, err.Error())
+		}
+		applied = append(applied, "code="+code)
+	}
+
+	var (
+		mediaType string
+		body      []byte
+	)
+	if res := o.responses[key]; res != nil && len(res.contents) > 0 {
+		content := res.negotiate(c.Request().Header.Get("Accept"))
+		mediaType, body = content.mediaType, content.body
+
+		if name, ok := preferences["example"]; ok {
+			if body, ok = content.examples[name]; !ok {
+				return c.String(http.StatusBadRequest, fmt.Sprintf("mock: no example %q of the %s response", name, key))
+			}
+			applied = append(applied, "example="+name)
+		}
+	}
+
+	if len(applied) > 0 {
+		c.Response().Header().Set("Preference-Applied", strings.Join(applied, ", "))
+	}
+	if body == nil {
+		return c.NoContent(status)
+	}
+
+	return c.Blob(status, mediaType, body)
+}
+
+// selectResponse returns the key of the response documenting the status code and the
+// status code itself.
+func (o *operation) selectResponse(code string) (string, int, error) {
+	status, ok := parseStatus(code)
+	if !ok {
+		return "", 0, fmt.Errorf("mock: invalid status code %q", code)
+	}
+
+	for _, key := range []string{code, code[:1] + "XX", "default"} {
+		if _, ok := o.responses[key]; ok {
+			return key, status, nil
+		}
+	}
+
+	return "", 0, fmt.Errorf("mock: no %s response in the API definition", code)
+}
+
+// negotiate returns the content of the first media type accepted, or the preferred one.
+func (r *response) negotiate(accept string) *content {
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mediaRange, _, _ = strings.Cut(mediaRange, ";")
+		mediaRange = strings.TrimSpace(mediaRange)
+		for _, content := range r.contents {
+			if mediaTypeMatches(mediaRange, content.mediaType) {
+				return content
+			}
+		}
+	}
+
+	return r.contents[0]
+}
+
+func mediaTypeMatches(mediaRange, mediaType string) bool {
+	mediaType, _, _ = strings.Cut(mediaType, ";")
+	if prefix, ok := strings.CutSuffix(mediaRange, "/*"); ok {
+		return strings.HasPrefix(mediaType, prefix+"/")
+	}
+
+	return strings.EqualFold(mediaRange, mediaType)
+}
+
+// parsePrefer returns the preferences of the Prefer headers, e.g. `code=404`.
+func parsePrefer(values []string) map[string]string {
+	preferences := map[string]string{}
+	for _, value := range values {
+		for _, preference := range strings.Split(value, ",") {
+			preference, _, _ = strings.Cut(preference, ";")
+			name, value, _ := strings.Cut(preference, "=")
+			preferences[strings.ToLower(strings.TrimSpace(name))] = strings.Trim(strings.TrimSpace(value), `"`)
+		}
+	}
+
+	return preferences
+}
+
+// parseStatus parses a three digit status code.
+func parseStatus(code string) (int, bool) {
+	if len(code) != 3 || code[0] < '1' || code[0] > '5' {
+		return 0, false
+	}
+
+	status := 0
+	for _, digit := range code {
+		if digit < '0' || digit > '9' {
+			return 0, false
+		}
+		status = status*10 + int(digit-'0')
+	}
+
+	return status, true
+}
+
+// statusCode returns the status code of a response key: the lowest status of a "2XX"
+// style range, 200 for "default".
+func statusCode(key string) int {
+	if status, ok := parseStatus(key); ok {
+		return status
+	}
+	if len(key) == 3 && strings.EqualFold(key[1:], "XX") && key[0] >= '1' && key[0] <= '5' {
+		return int(key[0]-'0') * 100
+	}
+
+	return http.StatusOK
+}
+
+// responseKey normalizes the key of a response, so that "2xx" matches "2XX".
+func responseKey(key string) string {
+	if strings.EqualFold(key, "default") {
+		return "default"
+	}
+
+	return strings.ToUpper(key)
+}
+
+// defaultResponseKey returns the first 2xx response, else the first response by status.
+func defaultResponseKey(responses map[string]*response) string {
+	keys := sortedKeys(responses)
+	for _, key := range keys {
+		if key[0] == '2' {
+			return key
+		}
+	}
+	if len(keys) > 0 {
+		// "default" sorts after the status codes.
+		return keys[0]
+	}
+
+	return ""
+}
+
+// spec is a JSON API definition, Swagger 2.0 or OpenAPI 3.
+type spec struct {
+	doc     map[string]any
+	swagger bool
+}
+
+func newSpec(doc []byte) (*spec, error) {
+	s := &spec{}
+	if err := json.Unmarshal(doc, &s.doc); err != nil {
+		return nil, fmt.Errorf("mock: parsing the API definition: %w", err)
+	}
+	s.swagger = s.doc["swagger"] != nil
+
+	return s, nil
+}
+
+// basePath returns the basePath of a Swagger 2.0 definition, the path of the first server
+// url of an OpenAPI 3 one.
+func (s *spec) basePath() string {
+	if s.swagger {
+		basePath, _ := s.doc["basePath"].(string)
+		return basePath
+	}
+
+	servers, _ := s.doc["servers"].([]any)
+	if len(servers) == 0 {
+		return ""
+	}
+	server, _ := servers[0].(map[string]any)
+	serverURL, _ := server["url"].(string)
+	u, err := url.Parse(serverURL)
+	if err != nil {
+		return ""
+	}
+
+	return u.Path
+}
+
+// operation returns the example responses of op.
+func (s *spec) operation(op map[string]any) (*operation, error) {
+	o := &operation{responses: map[string]*response{}}
+
+	responses, _ := op["responses"].(map[string]any)
+	for key, value := range responses {
+		definition, ok := s.resolve(value).(map[string]any)
+		if !ok {
+			continue
+		}
+
+		var (
+			res *response
+			err error
+		)
+		if s.swagger {
+			res, err = s.swaggerResponse(op, definition)
+		} else {
+			res, err = s.openAPIResponse(definition)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s response: %w", key, err)
+		}
+		o.responses[responseKey(key)] = res
+	}
+	o.defaultKey = defaultResponseKey(o.responses)
+
+	return o, nil
+}
+
+// swaggerResponse returns the examples of a Swagger 2.0 response, for every media type
+// the operation produces.
+func (s *spec) swaggerResponse(op, definition map[string]any) (*response, error) {
+	examples, _ := definition["examples"].(map[string]any)
+	schema, hasSchema := definition["schema"]
+	if len(examples) == 0 && !hasSchema {
+		return &response{}, nil
+	}
+
+	produces := stringSlice(op["produces"])
+	if produces == nil {
+		produces = stringSlice(s.doc["produces"])
+	}
+	for mediaType := range examples {
+		if !slices.Contains(produces, mediaType) {
+			produces = append(produces, mediaType)
+		}
+	}
+	if len(produces) == 0 {
+		produces = []string{echo.MIMEApplicationJSON}
+	}
+
+	res := &response{}
+	for _, mediaType := range preferredMediaTypes(produces) {
+		example, ok := examples[mediaType]
+		if !ok {
+			example = s.example(schema, nil)
+		}
+
+		body, err := encode(mediaType, example)
+		if err != nil {
+			return nil, err
+		}
+		res.contents = append(res.contents, &content{mediaType: mediaType, body: body})
+	}
+
+	return res, nil
+}
+
+// openAPIResponse returns the examples of an OpenAPI 3 response, for every media type of
+// its content.
+func (s *spec) openAPIResponse(definition map[string]any) (*response, error) {
+	contents, _ := definition["content"].(map[string]any)
+
+	res := &response{}
+	for _, mediaType := range preferredMediaTypes(sortedKeys(contents)) {
+		mediaTypeObject, _ := s.resolve(contents[mediaType]).(map[string]any)
+
+		c := &content{mediaType: mediaType, examples: map[string][]byte{}}
+		examples, _ := mediaTypeObject["examples"].(map[string]any)
+		for _, name := range sortedKeys(examples) {
+			example, _ := s.resolve(examples[name]).(map[string]any)
+			value, ok := example["value"]
+			if !ok {
+				continue
+			}
+
+			body, err := encode(mediaType, value)
+			if err != nil {
+				return nil, err
+			}
+			c.examples[name] = body
+			if c.body == nil {
+				c.body = body
+			}
+		}
+
+		example, ok := mediaTypeObject["example"]
+		if !ok {
+			if schema, hasSchema := mediaTypeObject["schema"]; hasSchema && c.body == nil {
+				example, ok = s.example(schema, nil), true
+			}
+		}
+		if ok {
+			body, err := encode(mediaType, example)
+			if err != nil {
+				return nil, err
+			}
+			c.body = body
+		}
+
+		res.contents = append(res.contents, c)
+	}
+
+	return res, nil
+}
+
+// preferredMediaTypes moves application/json, or else the first JSON media type, first.
+func preferredMediaTypes(mediaTypes []string) []string {
+	i := slices.Index(mediaTypes, echo.MIMEApplicationJSON)
+	if i < 0 {
+		i = slices.IndexFunc(mediaTypes, isJSON)
+	}
+	if i <= 0 {
+		return mediaTypes
+	}
+
+	preferred := []string{mediaTypes[i]}
+	preferred = append(preferred, mediaTypes[:i]...)
+
+	return append(preferred, mediaTypes[i+1:]...)
+}
+
+func isJSON(mediaType string) bool {
+	mediaType, _, _ = strings.Cut(mediaType, ";")
+	return strings.HasSuffix(mediaType, "/json") || strings.HasSuffix(mediaType, "+json")
+}
+
+// encode returns the payload of example: a string as is for a media type other than JSON,
+// JSON otherwise.
+func encode(mediaType string, example any) ([]byte, error) {
+	if s, ok := example.(string); ok && !isJSON(mediaType) {
+		return []byte(s), nil
+	}
+
+	return json.Marshal(example)
+}
+
+func stringSlice(value any) []string {
+	values, _ := value.([]any)
+
+	var s []string
+	for _, v := range values {
+		if v, ok := v.(string); ok {
+			s = append(s, v)
+		}
+	}
+
+	return s
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	return keys
+}
@@ -0,0 +1,275 @@
+package mock
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+	echoSwagger "github.com/swaggo/echo-swagger/v2"
+	"github.com/swaggo/swag"
+)
+
+type mockedSwag struct{}
+
+func (s *mockedSwag) ReadDoc() string {
+	return `{
+    "swagger": "2.0",
+    "info": {
+        "title": "Swagger Example API",
+        "version": "1.0"
+    },
+    "host": "petstore.swagger.io",
+    "basePath": "/v2",
+    "paths": {
+        "/pets": {
+            "get": {
+                "produces": ["application/json", "application/xml"],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {"type": "array", "items": {"$ref": "#/definitions/model.Pet"}}
+                    }
+                }
+            },
+            "post": {
+                "responses": {
+                    "201": {"description": "Created"},
+                    "400": {"$ref": "#/responses/BadRequest"}
+                }
+            }
+        },
+        "/pets/{id}": {
+            "get": {
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {"$ref": "#/definitions/model.Pet"}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "examples": {"application/json": {"message": "pet not found"}}
+                    },
+                    "default": {
+                        "description": "Error",
+                        "schema": {"$ref": "#/definitions/model.Error"}
+                    }
+                }
+            }
+        }
+    },
+    "responses": {
+        "BadRequest": {
+            "description": "Bad Request",
+            "schema": {"$ref": "#/definitions/model.Error"}
+        }
+    },
+    "definitions": {
+        "model.Error": {
+            "type": "object",
+            "properties": {
+                "message": {"type": "string", "example": "something went wrong"}
+            }
+        },
+        "model.Pet": {
+            "type": "object",
+            "properties": {
+                "id": {"type": "integer", "minimum": 1},
+                "name": {"type": "string", "example": "doggie"},
+                "status": {"type": "string", "enum": ["available", "sold"]},
+                "birthday": {"type": "string", "format": "date"},
+                "vaccinated": {"type": "boolean"},
+                "tags": {"type": "array", "items": {"type": "string"}},
+                "parent": {"$ref": "#/definitions/model.Pet"}
+            }
+        }
+    }
+}`
+}
+
+const openAPIDoc = `{
+    "openapi": "3.1.0",
+    "info": {"title": "Swagger Example API", "version": "1.0"},
+    "servers": [{"url": "https://api.example.com/api/v1/"}],
+    "paths": {
+        "/accounts/{id}": {
+            "get": {
+                "responses": {
+                    "2XX": {
+                        "description": "OK",
+                        "content": {
+                            "text/plain": {"example": "account 1"},
+                            "application/json": {
+                                "examples": {
+                                    "admin": {"value": {"id": 1, "role": "admin"}},
+                                    "guest": {"$ref": "#/components/examples/guest"}
+                                }
+                            }
+                        }
+                    },
+                    "4XX": {
+                        "description": "Client error",
+                        "content": {
+                            "application/problem+json": {
+                                "schema": {
+                                    "allOf": [
+                                        {"$ref": "#/components/schemas/Problem"},
+                                        {"type": "object", "properties": {"account": {"type": ["integer", "null"]}}}
+                                    ]
+                                }
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "responses": {"204": {"description": "No Content"}}
+            }
+        }
+    },
+    "components": {
+        "examples": {
+            "guest": {"value": {"id": 2, "role": "guest"}}
+        },
+        "schemas": {
+            "Problem": {
+                "type": "object",
+                "properties": {
+                    "title": {"type": "string", "examples": ["Not Found"]},
+                    "status": {"oneOf": [{"type": "integer"}, {"type": "string"}]}
+                }
+            }
+        }
+    }
+}`
+
+func init() {
+	swag.Register(swag.Name, &mockedSwag{})
+}
+
+func performRequest(router *echo.Echo, method, target string, headers ...string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, target, nil)
+	for i := 0; i+1 < len(headers); i += 2 {
+		r.Header.Add(headers[i], headers[i+1])
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	return w
+}
+
+func TestOptions(t *testing.T) {
+	cfg := newConfig()
+	assert.Equal(t, swag.Name, cfg.InstanceName)
+	assert.NotNil(t, cfg.DocProvider)
+
+	provider := echoSwagger.StaticDoc([]byte(openAPIDoc))
+	cfg = newConfig(InstanceName("admin"), Provider(provider), BasePath("/mock"))
+	assert.Equal(t, "admin", cfg.InstanceName)
+	assert.Equal(t, "/mock", cfg.BasePath)
+}
+
+func TestRegisterSwagger(t *testing.T) {
+	router := echo.New()
+	assert.NoError(t, Register(router))
+
+	w1 := performRequest(router, http.MethodGet, "/v2/pets/1")
+	assert.Equal(t, http.StatusOK, w1.Code)
+	assert.Equal(t, echo.MIMEApplicationJSON, w1.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"id": 1, "name": "doggie", "status": "available", "birthday": "2017-07-21", "vaccinated": true, "tags": ["string"]}`, w1.Body.String())
+	assert.Empty(t, w1.Header().Get("Preference-Applied"))
+
+	w2 := performRequest(router, http.MethodGet, "/v2/pets/1", "Prefer", "code=404")
+	assert.Equal(t, http.StatusNotFound, w2.Code)
+	assert.JSONEq(t, `{"message": "pet not found"}`, w2.Body.String())
+	assert.Equal(t, "code=404", w2.Header().Get("Preference-Applied"))
+
+	// Status codes without a response of their own fall back to the default response.
+	w3 := performRequest(router, http.MethodGet, "/v2/pets/1", "Prefer", "respond-async, code=503")
+	assert.Equal(t, http.StatusServiceUnavailable, w3.Code)
+	assert.JSONEq(t, `{"message": "something went wrong"}`, w3.Body.String())
+
+	w4 := performRequest(router, http.MethodGet, "/v2/pets", "Accept", "application/xml")
+	assert.Equal(t, http.StatusOK, w4.Code)
+	assert.Equal(t, echo.MIMEApplicationXML, w4.Header().Get("Content-Type"))
+
+	w5 := performRequest(router, http.MethodPost, "/v2/pets")
+	assert.Equal(t, http.StatusCreated, w5.Code)
+	assert.Empty(t, w5.Body.String())
+
+	w6 := performRequest(router, http.MethodPost, "/v2/pets", "Prefer", "code=400")
+	assert.Equal(t, http.StatusBadRequest, w6.Code)
+	assert.JSONEq(t, `{"message": "something went wrong"}`, w6.Body.String())
+
+	w7 := performRequest(router, http.MethodPost, "/v2/pets", "Prefer", "code=500")
+	assert.Equal(t, http.StatusBadRequest, w7.Code)
+	assert.Equal(t, "mock: no 500 response in the API definition", w7.Body.String())
+
+	w8 := performRequest(router, http.MethodPost, "/v2/pets", "Prefer", "code=abc")
+	assert.Equal(t, http.StatusBadRequest, w8.Code)
+	assert.Equal(t, `mock: invalid status code "abc"`, w8.Body.String())
+
+	assert.Equal(t, http.StatusMethodNotAllowed, performRequest(router, http.MethodDelete, "/v2/pets").Code)
+}
+
+func TestRegisterOpenAPI(t *testing.T) {
+	router := echo.New()
+	assert.NoError(t, Register(router, Provider(echoSwagger.StaticDoc([]byte(openAPIDoc)))))
+
+	w1 := performRequest(router, http.MethodGet, "/api/v1/accounts/1")
+	assert.Equal(t, http.StatusOK, w1.Code)
+	assert.Equal(t, echo.MIMEApplicationJSON, w1.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"id": 1, "role": "admin"}`, w1.Body.String())
+
+	w2 := performRequest(router, http.MethodGet, "/api/v1/accounts/1", "Prefer", "example=guest")
+	assert.Equal(t, http.StatusOK, w2.Code)
+	assert.JSONEq(t, `{"id": 2, "role": "guest"}`, w2.Body.String())
+	assert.Equal(t, "example=guest", w2.Header().Get("Preference-Applied"))
+
+	w3 := performRequest(router, http.MethodGet, "/api/v1/accounts/1", "Prefer", `example="unknown"`)
+	assert.Equal(t, http.StatusBadRequest, w3.Code)
+	assert.Equal(t, `mock: no example "unknown" of the 2XX response`, w3.Body.String())
+
+	w4 := performRequest(router, http.MethodGet, "/api/v1/accounts/1", "Accept", "text/*")
+	assert.Equal(t, http.StatusOK, w4.Code)
+	assert.Equal(t, echo.MIMETextPlain, w4.Header().Get("Content-Type"))
+	assert.Equal(t, "account 1", w4.Body.String())
+
+	w5 := performRequest(router, http.MethodGet, "/api/v1/accounts/1", "Prefer", "code=404")
+	assert.Equal(t, http.StatusNotFound, w5.Code)
+	assert.Equal(t, "application/problem+json", w5.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"title": "Not Found", "status": 0, "account": 0}`, w5.Body.String())
+
+	w6 := performRequest(router, http.MethodGet, "/api/v1/accounts/1", "Prefer", "code=201", "Prefer", "example=guest")
+	assert.Equal(t, http.StatusCreated, w6.Code)
+	assert.JSONEq(t, `{"id": 2, "role": "guest"}`, w6.Body.String())
+	assert.Equal(t, "code=201, example=guest", w6.Header().Get("Preference-Applied"))
+
+	w7 := performRequest(router, http.MethodDelete, "/api/v1/accounts/1")
+	assert.Equal(t, http.StatusNoContent, w7.Code)
+	assert.Empty(t, w7.Body.String())
+}
+
+func TestRegisterGroup(t *testing.T) {
+	router := echo.New()
+	assert.NoError(t, Register(router.Group("/mock"), BasePath("/")))
+
+	assert.Equal(t, http.StatusOK, performRequest(router, http.MethodGet, "/mock/pets/1").Code)
+	assert.Equal(t, http.StatusNotFound, performRequest(router, http.MethodGet, "/v2/pets/1").Code)
+}
+
+func TestRegisterRequestProvider(t *testing.T) {
+	router := echo.New()
+	assert.NoError(t, Register(router, Provider(echoSwagger.DocProviderFunc(func(c *echo.Context, _ string) ([]byte, error) {
+		return []byte(`{"swagger": "2.0", "host": "` + c.Request().Host + `", "paths": {"/health": {"get": {"responses": {"204": {"description": "ok"}}}}}}`), nil
+	}))))
+
+	assert.Equal(t, http.StatusNoContent, performRequest(router, http.MethodGet, "/health").Code)
+}
+
+func TestRegisterErrors(t *testing.T) {
+	assert.ErrorContains(t, Register(echo.New(), InstanceName("unknown")), `mock: reading the API definition of "unknown"`)
+	assert.ErrorContains(t, Register(echo.New(), Provider(echoSwagger.DocProviderFunc(func(*echo.Context, string) ([]byte, error) {
+		return []byte(`[`), nil
+	}))), "mock: parsing the API definition")
+}
@@ -0,0 +1,51 @@
+package echoSwagger
+
+import (
+	"io/fs"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v5"
+)
+
+// oauth2RedirectPage is the page the IAM provider redirects to at the end of the OAuth2
+// authorization flows.
+const oauth2RedirectPage = "oauth2-redirect.html"
+
+// serveOAuth2Redirect serves oauth2-redirect.html. Its query string carries the
+// authorization code, so the page is neither cached nor sent as a referrer.
+func (config *Config) serveOAuth2Redirect(c *echo.Context) error {
+	page, err := fs.ReadFile(config.assetFS(oauth2RedirectPage), oauth2RedirectPage)
+	if err != nil {
+		return c.String(http.StatusNotFound, http.StatusText(http.StatusNotFound))
+	}
+
+	header := c.Response().Header()
+	header.Set("Content-Type", "text/html; charset=utf-8")
+	header.Set("Cache-Control", "no-store")
+	header.Set("Referrer-Policy", "no-referrer")
+
+	return c.HTMLBlob(http.StatusOK, page)
+}
+
+// oauth2RedirectURL returns the url of the oauth2-redirect.html served under basePath, as
+// seen by the client.
+func oauth2RedirectURL(c *echo.Context, basePath string) string {
+	path := strings.TrimSuffix(joinPrefix(requestPrefix(c), basePath), "/")
+
+	return c.Scheme() + "://" + requestHost(c) + path + "/" + oauth2RedirectPage
+}
+
+// setOAuth2RedirectURL sets the oauth2RedirectUrl of UIConfig to the page served by the
+// handler, unless Config.OAuth2RedirectURL is set.
+func (d *IndexData) setOAuth2RedirectURL(c *echo.Context) (err error) {
+	if d.Config.OAuth2RedirectURL != "" {
+		return nil
+	}
+
+	ui := *d.ui
+	ui.OAuth2RedirectURL = oauth2RedirectURL(c, d.BasePath)
+	d.UIConfig, err = jsonJS(&ui)
+
+	return err
+}
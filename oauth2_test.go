@@ -0,0 +1,49 @@
+package echoSwagger
+
+import (
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+	swaggerFiles "github.com/swaggo/files/v2"
+)
+
+func TestOAuth2RedirectPage(t *testing.T) {
+	expected, err := fs.ReadFile(swaggerFiles.FS, oauth2RedirectPage)
+	assert.NoError(t, err)
+
+	for _, wrap := range []func(options ...func(*Config)) echo.HandlerFunc{EchoWrapHandler, EchoWrapHandlerV3} {
+		router := echo.New()
+		admin := router.Group("/admin")
+		admin.GET("/swagger/*", wrap(OAuth(&OAuthConfig{ClientId: "docs"})))
+
+		w1 := performRequest(http.MethodGet, "/admin/swagger/oauth2-redirect.html?code=secret&state=abc", router)
+		assert.Equal(t, http.StatusOK, w1.Code)
+		assert.Equal(t, "text/html; charset=utf-8", w1.Header().Get("Content-Type"))
+		assert.Equal(t, "no-store", w1.Header().Get("Cache-Control"))
+		assert.Equal(t, "no-referrer", w1.Header().Get("Referrer-Policy"))
+		assert.Equal(t, string(expected), w1.Body.String())
+
+		w2 := performRequest(http.MethodGet, "/admin/swagger/index.html", router)
+		assert.Equal(t, http.StatusOK, w2.Code)
+		assert.Contains(t, w2.Body.String(), `"oauth2RedirectUrl":"http://example.com/admin/swagger/oauth2-redirect.html"`)
+
+		r := httptest.NewRequest(http.MethodGet, "/admin/swagger/index.html", nil)
+		r.Header.Set("X-Forwarded-Proto", "https")
+		r.Header.Set("X-Forwarded-Host", "docs.example.com")
+		r.Header.Set("X-Forwarded-Prefix", "/api/")
+		w3 := httptest.NewRecorder()
+		router.ServeHTTP(w3, r)
+		assert.Equal(t, http.StatusOK, w3.Code)
+		assert.Contains(t, w3.Body.String(), `"oauth2RedirectUrl":"https://docs.example.com/api/admin/swagger/oauth2-redirect.html"`)
+	}
+
+	router := echo.New()
+	router.GET("/admin/swagger/*", EchoWrapHandler(OAuth2RedirectURL("https://idp.example.com/callback")))
+
+	w := performRequest(http.MethodGet, "/admin/swagger/index.html", router)
+	assert.Contains(t, w.Body.String(), `"oauth2RedirectUrl":"https://idp.example.com/callback"`)
+}
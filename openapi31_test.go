@@ -0,0 +1,131 @@
+package echoSwagger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+	swagV3 "github.com/swaggo/swag/v2"
+)
+
+type mockedSwag31 struct{}
+
+func (s *mockedSwag31) ReadDoc() string {
+	return `{
+    "openapi": "3.1.0",
+    "info": {
+        "title": "Swagger Example API",
+        "version": "1.0"
+    },
+    "servers": [{"url": "https://petstore.swagger.io/v2"}],
+    "paths": {
+        "/pets/{id}": {
+            "get": {
+                "tags": ["public"],
+                "parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "integer"}}],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "content": {"application/json": {"schema": {"$ref": "#/components/schemas/web.Pet"}}}
+                    }
+                }
+            }
+        }
+    },
+    "webhooks": {
+        "newPet": {
+            "post": {
+                "tags": ["internal"],
+                "requestBody": {
+                    "content": {"application/json": {"schema": {"$ref": "#/components/schemas/web.PetEvent"}}}
+                },
+                "responses": {"200": {"description": "ok"}}
+            }
+        }
+    },
+    "components": {
+        "schemas": {
+            "web.Pet": {
+                "type": "object",
+                "properties": {
+                    "name": {"type": "string"},
+                    "tag": {"type": ["string", "null"]}
+                }
+            },
+            "web.PetEvent": {
+                "type": "object",
+                "properties": {
+                    "pet": {"$ref": "#/components/schemas/web.Pet"},
+                    "at": {"type": ["string", "null"], "format": "date-time"}
+                }
+            },
+            "web.Unused": {"type": "object"}
+        }
+    }
+}`
+}
+
+func TestDocVersion(t *testing.T) {
+	assert.Equal(t, "2.0", docVersion([]byte((&mockedSwag{}).ReadDoc())))
+	assert.Equal(t, "3.1.0", docVersion([]byte((&mockedSwag31{}).ReadDoc())))
+	assert.Equal(t, "", docVersion([]byte(`not json`)))
+
+	assert.True(t, isOpenAPI31("3.1.0"))
+	assert.True(t, isOpenAPI31("3.1"))
+	assert.False(t, isOpenAPI31("3.0.3"))
+	assert.False(t, isOpenAPI31("3.10.0"))
+}
+
+func TestWrapHandlerV3OpenAPI31(t *testing.T) {
+	doc := &mockedSwag31{}
+	swagV3.Register("openapi31", doc)
+
+	router := echo.New()
+	router.GET("/swagger/*", EchoWrapHandlerV3(InstanceName("openapi31")))
+
+	w1 := performRequest(http.MethodGet, "/swagger/doc.json", router)
+	assert.Equal(t, http.StatusOK, w1.Code)
+	assert.Equal(t, "application/openapi+json; charset=utf-8", w1.Header().Get("Content-Type"))
+	assert.Equal(t, doc.ReadDoc(), w1.Body.String())
+
+	w2 := performRequest(http.MethodGet, "/swagger/doc.yaml", router)
+	assert.Equal(t, http.StatusOK, w2.Code)
+	assert.Equal(t, "application/openapi+yaml; charset=utf-8", w2.Header().Get("Content-Type"))
+	assert.Contains(t, w2.Body.String(), "openapi: 3.1.0\n")
+	assert.Contains(t, w2.Body.String(), "webhooks:\n  newPet:\n")
+	assert.Contains(t, w2.Body.String(), "type:\n          - string\n          - \"null\"\n")
+
+	// Swagger 2.0 documents keep the media types of their file extension.
+	router = echo.New()
+	router.GET("/swagger/*", EchoWrapHandlerV3(Provider(StaticDoc([]byte((&mockedSwag{}).ReadDoc())))))
+	assert.Equal(t, "application/json; charset=utf-8", performRequest(http.MethodGet, "/swagger/doc.json", router).Header().Get("Content-Type"))
+
+	// swag v1 documents are always served as Swagger 2.0 and OpenAPI 3.0.
+	router = echo.New()
+	router.GET("/swagger/*", EchoWrapHandler(Provider(StaticDoc([]byte(doc.ReadDoc())))))
+	assert.Equal(t, "application/json; charset=utf-8", performRequest(http.MethodGet, "/swagger/doc.json", router).Header().Get("Content-Type"))
+}
+
+func TestOpenAPI31FilterAndDynamicHost(t *testing.T) {
+	router := echo.New()
+	router.GET("/swagger/*", EchoWrapHandlerV3(
+		Provider(StaticDoc([]byte((&mockedSwag31{}).ReadDoc()))),
+		Filter(IncludeTags("public")),
+		DynamicHost(true),
+	))
+
+	r := httptest.NewRequest(http.MethodGet, "/swagger/doc.json", nil)
+	r.Host = "localhost:1323"
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/openapi+json; charset=utf-8", w.Header().Get("Content-Type"))
+
+	body := w.Body.String()
+	assert.Contains(t, body, `"servers":[{"url":"http://localhost:1323/v2"}]`)
+	assert.Contains(t, body, `"type":["string","null"]`)
+	assert.Contains(t, body, `"web.PetEvent"`)
+	assert.NotContains(t, body, `"web.Unused"`)
+}
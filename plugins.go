@@ -0,0 +1,99 @@
+package echoSwagger
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v5"
+)
+
+// pluginsDir is the directory, next to index.html, serving the plugin scripts read from a
+// file system.
+const pluginsDir = "plugins/"
+
+// Plugin is a Swagger UI plugin loaded by the Swagger UI page. See
+// https://swagger.io/docs/open-source-tools/swagger-ui/customization/plugin-api/ for further
+// details.
+type Plugin struct {
+	// The name of the global variable the script assigns the plugin to.
+	Name string
+
+	// The url of the script, e.g. a CDN. Ignored if FS is set.
+	URL string
+
+	// The file system serving the script at Path, under plugins/ next to index.html.
+	FS   fs.FS
+	Path string
+}
+
+// ScriptURL returns the url the Swagger UI page loads the plugin script from.
+func (p Plugin) ScriptURL() string {
+	if p.FS != nil {
+		return "./" + pluginsDir + p.Path
+	}
+
+	return p.URL
+}
+
+// PluginURL registers the Swagger UI plugin the script at url assigns to the global
+// variable name.
+func PluginURL(name, url string) func(*Config) {
+	return func(c *Config) {
+		c.Plugins = append(c.Plugins, Plugin{Name: name, URL: url})
+	}
+}
+
+// PluginFS registers the Swagger UI plugin the script at path in fsys assigns to the global
+// variable name. The script is served under plugins/ next to index.html.
+func PluginFS(name string, fsys fs.FS, path string) func(*Config) {
+	return func(c *Config) {
+		c.Plugins = append(c.Plugins, Plugin{Name: name, FS: fsys, Path: path})
+	}
+}
+
+// validatePlugins reports the first plugin missing its name or script.
+func (config *Config) validatePlugins() error {
+	for _, plugin := range config.Plugins {
+		if plugin.Name == "" {
+			return errors.New("echoSwagger: Plugin requires a Name")
+		}
+		if plugin.FS != nil && !fs.ValidPath(plugin.Path) {
+			return fmt.Errorf("echoSwagger: invalid Path %q of Plugin %q", plugin.Path, plugin.Name)
+		}
+		if plugin.FS == nil && plugin.URL == "" {
+			return fmt.Errorf("echoSwagger: Plugin %q requires a URL or an FS", plugin.Name)
+		}
+	}
+
+	return nil
+}
+
+// servePlugin serves the plugin script at wildcard, under plugins/. It returns false if no
+// plugin is served there.
+func (config *Config) servePlugin(c *echo.Context, wildcard string) (bool, error) {
+	path, ok := strings.CutPrefix(wildcard, pluginsDir)
+	if !ok {
+		return false, nil
+	}
+
+	for _, plugin := range config.Plugins {
+		if plugin.FS == nil || plugin.Path != path {
+			continue
+		}
+
+		script, err := fs.ReadFile(plugin.FS, plugin.Path)
+		if err != nil {
+			return true, c.String(http.StatusNotFound, http.StatusText(http.StatusNotFound))
+		}
+		if config.AssetsCacheControl != "" {
+			c.Response().Header().Set("Cache-Control", config.AssetsCacheControl)
+		}
+
+		return true, c.Blob(http.StatusOK, "application/javascript", script)
+	}
+
+	return false, nil
+}
@@ -0,0 +1,139 @@
+package echoSwagger
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPluginOptions(t *testing.T) {
+	fsys := fstest.MapFS{}
+	cfg := newConfig(
+		PluginURL("HierarchicalTagsPlugin", "https://unpkg.com/swagger-ui-plugin-hierarchical-tags"),
+		PluginFS("CopyAsCurlPlugin", fsys, "copy-as-curl.js"),
+		DownloadURLPlugin(false),
+		Layout("BaseLayout"),
+		HideTopbar(true),
+	)
+
+	assert.Equal(t, []Plugin{
+		{Name: "HierarchicalTagsPlugin", URL: "https://unpkg.com/swagger-ui-plugin-hierarchical-tags"},
+		{Name: "CopyAsCurlPlugin", FS: fsys, Path: "copy-as-curl.js"},
+	}, cfg.Plugins)
+	assert.False(t, cfg.DownloadURLPlugin)
+	assert.Equal(t, "BaseLayout", cfg.Layout)
+	assert.True(t, cfg.HideTopbar)
+
+	assert.Equal(t, "https://unpkg.com/swagger-ui-plugin-hierarchical-tags", cfg.Plugins[0].ScriptURL())
+	assert.Equal(t, "./plugins/copy-as-curl.js", cfg.Plugins[1].ScriptURL())
+	assert.NoError(t, cfg.validate())
+
+	assert.EqualError(t, newConfig(Layout("SidebarLayout")).checkFields(),
+		`echoSwagger: invalid Layout "SidebarLayout", want one of "StandaloneLayout", "BaseLayout"`)
+	assert.EqualError(t, newConfig(DownloadURLPlugin(false)).validate(), "echoSwagger: DownloadURLPlugin(false) requires a Plugin loading the API definition")
+	assert.EqualError(t, newConfig(PluginURL("", "plugin.js")).validate(), "echoSwagger: Plugin requires a Name")
+	assert.EqualError(t, newConfig(PluginURL("CopyAsCurlPlugin", "")).validate(), `echoSwagger: Plugin "CopyAsCurlPlugin" requires a URL or an FS`)
+	assert.EqualError(t, newConfig(PluginFS("CopyAsCurlPlugin", fsys, "../copy-as-curl.js")).validate(),
+		`echoSwagger: invalid Path "../copy-as-curl.js" of Plugin "CopyAsCurlPlugin"`)
+}
+
+func TestPluginsHandler(t *testing.T) {
+	plugins := fstest.MapFS{
+		"copy-as-curl.js": {Data: []byte("window.CopyAsCurlPlugin = function() { return {}; };")},
+	}
+
+	for _, wrap := range []func(options ...func(*Config)) echo.HandlerFunc{EchoWrapHandler, EchoWrapHandlerV3} {
+		router := echo.New()
+		router.GET("/swagger/*", wrap(
+			PluginFS("CopyAsCurlPlugin", plugins, "copy-as-curl.js"),
+			PluginURL("HierarchicalTagsPlugin", "https://unpkg.com/swagger-ui-plugin-hierarchical-tags"),
+			DownloadURLPlugin(false),
+			HideTopbar(true),
+		))
+
+		w1 := performRequest(http.MethodGet, "/swagger/index.html", router)
+		assert.Equal(t, http.StatusOK, w1.Code)
+		body := w1.Body.String()
+		assert.Contains(t, body, `<script src="./plugins/copy-as-curl.js"> </script>`)
+		assert.Contains(t, body, `<script src="https://unpkg.com/swagger-ui-plugin-hierarchical-tags"> </script>`)
+		assert.Contains(t, body, `window["CopyAsCurlPlugin"],`)
+		assert.Contains(t, body, `window["HierarchicalTagsPlugin"],`)
+		assert.NotContains(t, body, `SwaggerUIBundle.plugins.DownloadUrl`)
+		assert.Contains(t, body, `.swagger-ui .topbar
+    {
+        display: none;
+    }`)
+		assert.Contains(t, body, `"layout":"StandaloneLayout"`)
+
+		w2 := performRequest(http.MethodGet, "/swagger/plugins/copy-as-curl.js", router)
+		assert.Equal(t, http.StatusOK, w2.Code)
+		assert.Equal(t, "application/javascript", w2.Header().Get("Content-Type"))
+		assert.Equal(t, "window.CopyAsCurlPlugin = function() { return {}; };", w2.Body.String())
+
+		assert.Equal(t, http.StatusNotFound, performRequest(http.MethodGet, "/swagger/plugins/unknown.js", router).Code)
+	}
+
+	router := echo.New()
+	router.GET("/swagger/*", EchoWrapHandler(Layout("BaseLayout")))
+
+	body := performRequest(http.MethodGet, "/swagger/index.html", router).Body.String()
+	assert.Contains(t, body, `SwaggerUIBundle.plugins.DownloadUrl,`)
+	assert.Contains(t, body, `"layout":"BaseLayout"`)
+	assert.NotContains(t, body, `display: none;`)
+
+	// Plugins from another origin load under the Content-Security-Policy.
+	router = echo.New()
+	router.GET("/swagger/*", EchoWrapHandler(
+		PluginURL("HierarchicalTagsPlugin", "https://unpkg.com/swagger-ui-plugin-hierarchical-tags"),
+		SecurityHeaders(&SecurityHeadersConfig{}),
+	))
+
+	w := performRequest(http.MethodGet, "/swagger/index.html", router)
+	nonce := regexp.MustCompile(`'nonce-([^']+)'`).FindStringSubmatch(w.Header().Get("Content-Security-Policy"))
+	if assert.Len(t, nonce, 2) {
+		assert.Contains(t, w.Body.String(), `<script src="https://unpkg.com/swagger-ui-plugin-hierarchical-tags" nonce="`+nonce[1]+`"> </script>`)
+	}
+}
+
+// loadsSpec reports whether the Swagger UI page in body loads an API definition on its own,
+// following the conditions of swagger-ui-bundle.js: either url is set without urls, or the
+// topbar of StandaloneLayout loads one of urls, both through the DownloadUrl plugin.
+func loadsSpec(t *testing.T, body string) bool {
+	_, script, _ := strings.Cut(body, "SwaggerUIBundle(Object.assign(")
+
+	var ui struct {
+		URL    string  `json:"url"`
+		URLs   []uiURL `json:"urls"`
+		Layout string  `json:"layout"`
+	}
+	assert.NoError(t, json.NewDecoder(strings.NewReader(script)).Decode(&ui))
+
+	if !strings.Contains(body, "SwaggerUIBundle.plugins.DownloadUrl,") {
+		return false
+	}
+
+	return (ui.URL != "" && len(ui.URLs) == 0) || (ui.Layout == "StandaloneLayout" && len(ui.URLs) > 0)
+}
+
+func TestPluginsLayout(t *testing.T) {
+	for _, wrap := range []func(options ...func(*Config)) echo.HandlerFunc{EchoWrapHandler, EchoWrapHandlerV3} {
+		router := echo.New()
+		router.GET("/standalone/*", wrap(HideTopbar(true)))
+		router.GET("/base/*", wrap(Layout("BaseLayout"), SpecURLs(SpecURL{Name: "Admin API", URL: "admin.json", Primary: true})))
+
+		body := performRequest(http.MethodGet, "/standalone/index.html", router).Body.String()
+		assert.True(t, loadsSpec(t, body))
+		assert.Contains(t, body, `"urls":[{"name":"doc.json","url":"doc.json"},{"name":"doc.yaml","url":"doc.yaml"}],`)
+
+		body = performRequest(http.MethodGet, "/base/index.html", router).Body.String()
+		assert.True(t, loadsSpec(t, body))
+		assert.Contains(t, body, `{"url":"admin.json",`)
+		assert.NotContains(t, body, `"urls"`)
+	}
+}
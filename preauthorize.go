@@ -0,0 +1,21 @@
+package echoSwagger
+
+import "github.com/labstack/echo/v5"
+
+// setPreauthorization sets Preauthorization to the values returned by Config.Preauthorize
+// for the request. The page then holds credentials, so it must not be cached.
+func (d *IndexData) setPreauthorization(c *echo.Context) (err error) {
+	if d.Config.Preauthorize == nil {
+		return nil
+	}
+
+	values := d.Config.Preauthorize(c)
+	if len(values) == 0 {
+		return nil
+	}
+
+	c.Response().Header().Set("Cache-Control", "no-store")
+	d.Preauthorization, err = jsonJS(values)
+
+	return err
+}
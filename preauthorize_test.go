@@ -0,0 +1,49 @@
+package echoSwagger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreauthorizeOption(t *testing.T) {
+	var cfg Config
+	Preauthorize(func(*echo.Context) map[string]string { return nil })(&cfg)
+	assert.NotNil(t, cfg.Preauthorize)
+}
+
+func TestPreauthorizeHandler(t *testing.T) {
+	preauthorize := Preauthorize(func(c *echo.Context) map[string]string {
+		cookie, err := c.Cookie("session")
+		if err != nil {
+			return nil
+		}
+
+		return map[string]string{
+			"BearerAuth": "Bearer " + cookie.Value,
+			"BasicAuth":  "admin:</script>",
+		}
+	})
+
+	for _, wrap := range []func(options ...func(*Config)) echo.HandlerFunc{EchoWrapHandler, EchoWrapHandlerV3} {
+		router := echo.New()
+		router.GET("/swagger/*", wrap(preauthorize))
+
+		r := httptest.NewRequest(http.MethodGet, "/swagger/index.html", nil)
+		r.AddCookie(&http.Cookie{Name: "session", Value: "token"})
+		w1 := httptest.NewRecorder()
+		router.ServeHTTP(w1, r)
+		assert.Equal(t, http.StatusOK, w1.Code)
+		assert.Equal(t, "no-store", w1.Header().Get("Cache-Control"))
+		assert.Contains(t, w1.Body.String(), `var values = {"BasicAuth":"admin:\u003c/script\u003e","BearerAuth":"Bearer token"};`)
+		assert.Contains(t, w1.Body.String(), `ui.preauthorizeApiKey(name, values[name]);`)
+
+		w2 := performRequest(http.MethodGet, "/swagger/index.html", router)
+		assert.Equal(t, http.StatusOK, w2.Code)
+		assert.Empty(t, w2.Header().Get("Cache-Control"))
+		assert.NotContains(t, w2.Body.String(), `onComplete`)
+	}
+}
@@ -0,0 +1,124 @@
+package echoSwagger
+
+import (
+	"encoding/json"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/labstack/echo/v5"
+	"github.com/swaggo/swag"
+	swagV2 "github.com/swaggo/swag/v2"
+	"sigs.k8s.io/yaml"
+)
+
+// DocProvider provides the API definition served as doc.json and doc.yaml.
+type DocProvider interface {
+	// ReadDoc returns the JSON API definition of the given swag instance.
+	ReadDoc(c *echo.Context, instanceName string) ([]byte, error)
+}
+
+// DocProviderFunc is an adapter to allow the use of ordinary functions as DocProvider.
+// The function is called on every request of doc.json or doc.yaml, unless DocCache(true)
+// is set.
+type DocProviderFunc func(c *echo.Context, instanceName string) ([]byte, error)
+
+// ReadDoc calls f(c, instanceName).
+func (f DocProviderFunc) ReadDoc(c *echo.Context, instanceName string) ([]byte, error) {
+	return f(c, instanceName)
+}
+
+// staticDoc is a DocProvider returning the same document on every request, whose rendered
+// documents are cached by default.
+type staticDoc func(c *echo.Context, instanceName string) ([]byte, error)
+
+// ReadDoc calls f(c, instanceName).
+func (f staticDoc) ReadDoc(c *echo.Context, instanceName string) ([]byte, error) {
+	return f(c, instanceName)
+}
+
+var (
+	// SwagRegistry reads API definitions registered with github.com/swaggo/swag.
+	// It is the default of EchoWrapHandler.
+	SwagRegistry DocProvider = staticDoc(func(_ *echo.Context, instanceName string) ([]byte, error) {
+		doc, err := swag.ReadDoc(instanceName)
+		return []byte(doc), err
+	})
+
+	// SwagV2Registry reads API definitions registered with github.com/swaggo/swag/v2.
+	// It is the default of EchoWrapHandlerV3.
+	SwagV2Registry DocProvider = staticDoc(func(_ *echo.Context, instanceName string) ([]byte, error) {
+		doc, err := swagV2.ReadDoc(instanceName)
+		return []byte(doc), err
+	})
+)
+
+// StaticDoc serves the given API definition for every instance. The definition may be
+// either JSON or YAML.
+func StaticDoc(doc []byte) DocProvider {
+	var err error
+	if !json.Valid(doc) {
+		doc, err = yaml.YAMLToJSON(doc)
+	}
+
+	return staticDoc(func(_ *echo.Context, _ string) ([]byte, error) {
+		return doc, err
+	})
+}
+
+// FSDoc serves the API definition stored at name in fsys for every instance. Files with
+// a .yaml or .yml extension are converted to JSON.
+func FSDoc(fsys fs.FS, name string) DocProvider {
+	return staticDoc(func(_ *echo.Context, _ string) ([]byte, error) {
+		doc, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+
+		switch filepath.Ext(name) {
+		case ".yaml", ".yml":
+			return yaml.YAMLToJSON(doc)
+		}
+
+		return doc, nil
+	})
+}
+
+// docProvider returns the configured DocProvider, or fallback if none is configured,
+// wrapped according to config.
+func (config *Config) docProvider(fallback DocProvider) DocProvider {
+	provider := config.DocProvider
+	if provider == nil {
+		provider = fallback
+	}
+
+	if config.Filter != nil || config.RequestFilter != nil {
+		provider = filteredDoc(provider, config)
+	}
+
+	if config.DynamicHost {
+		provider = dynamicHost(provider)
+	}
+
+	return provider
+}
+
+// docVersion returns the `openapi` or `swagger` version field of a JSON API definition.
+func docVersion(doc []byte) string {
+	var version struct {
+		OpenAPI string `json:"openapi"`
+		Swagger string `json:"swagger"`
+	}
+	_ = json.Unmarshal(doc, &version)
+
+	if version.OpenAPI != "" {
+		return version.OpenAPI
+	}
+
+	return version.Swagger
+}
+
+// isOpenAPI31 reports whether version is an OpenAPI 3.1 version.
+func isOpenAPI31(version string) bool {
+	return version == "3.1" || strings.HasPrefix(version, "3.1.")
+}
@@ -0,0 +1,92 @@
+package echoSwagger
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProvider(t *testing.T) {
+	var cfg Config
+	expected := StaticDoc([]byte(`{}`))
+	Provider(expected)(&cfg)
+	assert.NotNil(t, cfg.DocProvider)
+}
+
+func TestStaticDoc(t *testing.T) {
+	doc := (&mockedSwag{}).ReadDoc()
+
+	for _, handler := range []echo.HandlerFunc{
+		EchoWrapHandler(Provider(StaticDoc([]byte(doc)))),
+		EchoWrapHandlerV3(Provider(StaticDoc([]byte(doc)))),
+	} {
+		router := echo.New()
+		router.GET("/*", handler)
+
+		w1 := performRequest(http.MethodGet, "/doc.json", router)
+		assert.Equal(t, http.StatusOK, w1.Code)
+		assert.Equal(t, "application/json; charset=utf-8", w1.Header().Get("Content-Type"))
+		assert.Equal(t, doc, w1.Body.String())
+
+		w2 := performRequest(http.MethodGet, "/doc.yaml", router)
+		assert.Equal(t, http.StatusOK, w2.Code)
+		assert.Contains(t, w2.Body.String(), "basePath: /v2")
+	}
+
+	router := echo.New()
+	router.GET("/*", EchoWrapHandler(Provider(StaticDoc([]byte("swagger: \"2.0\"\nbasePath: /v2\n")))))
+
+	w := performRequest(http.MethodGet, "/doc.json", router)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"swagger": "2.0", "basePath": "/v2"}`, w.Body.String())
+}
+
+func TestFSDoc(t *testing.T) {
+	fsys := fstest.MapFS{
+		"api/swagger.json": {Data: []byte(`{"swagger": "2.0"}`)},
+		"api/swagger.yaml": {Data: []byte("swagger: \"2.0\"\n")},
+	}
+
+	router := echo.New()
+	router.GET("/json/*", EchoWrapHandler(Provider(FSDoc(fsys, "api/swagger.json"))))
+	router.GET("/yaml/*", EchoWrapHandlerV3(Provider(FSDoc(fsys, "api/swagger.yaml"))))
+	router.GET("/missing/*", EchoWrapHandler(Provider(FSDoc(fsys, "api/missing.json"))))
+
+	w1 := performRequest(http.MethodGet, "/json/doc.json", router)
+	assert.Equal(t, http.StatusOK, w1.Code)
+	assert.Equal(t, `{"swagger": "2.0"}`, w1.Body.String())
+
+	w2 := performRequest(http.MethodGet, "/yaml/doc.json", router)
+	assert.Equal(t, http.StatusOK, w2.Code)
+	assert.JSONEq(t, `{"swagger": "2.0"}`, w2.Body.String())
+
+	assert.Equal(t, http.StatusInternalServerError, performRequest(http.MethodGet, "/missing/doc.json", router).Code)
+}
+
+func TestDocProviderFunc(t *testing.T) {
+	provider := DocProviderFunc(func(c *echo.Context, instanceName string) ([]byte, error) {
+		if instanceName == "broken" {
+			return nil, errors.New("broken document")
+		}
+		return []byte(`{"info": {"title": "` + instanceName + ` ` + c.Request().Header.Get("Accept-Language") + `"}}`), nil
+	})
+
+	router := echo.New()
+	router.GET("/*", EchoWrapHandler(Provider(provider), InstanceNames("admin", "broken")))
+
+	r := httptest.NewRequest(http.MethodGet, "/admin/doc.json", nil)
+	r.Header.Set("Accept-Language", "en")
+	w1 := httptest.NewRecorder()
+	router.ServeHTTP(w1, r)
+	assert.Equal(t, http.StatusOK, w1.Code)
+	assert.Equal(t, `{"info": {"title": "admin en"}}`, w1.Body.String())
+
+	w2 := performRequest(http.MethodGet, "/broken/doc.json", router)
+	assert.Equal(t, http.StatusInternalServerError, w2.Code)
+	assert.Equal(t, "broken document", w2.Body.String())
+}
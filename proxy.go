@@ -0,0 +1,263 @@
+package echoSwagger
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v5"
+)
+
+// proxyDir is the directory, next to index.html, the "Try it out" proxy is mounted on.
+// Requests to proxy/<scheme>/<host>/<path> are forwarded to <scheme>://<host>/<path>.
+const proxyDir = "proxy/"
+
+// ProxyConfig stores configuration for the "Try it out" proxy, which forwards the requests
+// of Swagger UI to the API hosts so that they are not subject to CORS.
+type ProxyConfig struct {
+	// The hosts, with their port if any, requests may be forwarded to. Default is the hosts
+	// of the API definitions of the handler, as provided rather than rewritten by
+	// DynamicHost from the request: host for Swagger 2.0, servers for OpenAPI 3.
+	AllowedHosts []string
+
+	// The request headers forwarded to the API. Every header but the denied ones if empty.
+	AllowedHeaders []string
+
+	// The request headers never forwarded to the API. Default is `Cookie`, so that the
+	// cookies of the documentation host do not leak. The Authorization header holding the
+	// BasicAuth credentials of the documentation is never forwarded either.
+	DeniedHeaders []string
+
+	// The time limit of a forwarded request. Default is 30 seconds.
+	Timeout time.Duration
+
+	// The maximum size of the forwarded request and response bodies. Default is 10 MiB.
+	MaxBodySize int64
+}
+
+// errBodyTooLarge reports a response body exceeding ProxyConfig.MaxBodySize.
+var errBodyTooLarge = errors.New("echoSwagger: proxied response body too large")
+
+// tryItOutProxy forwards the "Try it out" requests.
+type tryItOutProxy struct {
+	allowedHosts   []string
+	allowedHeaders []string
+	deniedHeaders  []string
+	timeout        time.Duration
+	maxBodySize    int64
+
+	// The hosts of the API definitions of the handler, used if allowedHosts is empty.
+	specHosts func(c *echo.Context) []string
+
+	// The credentials of the documentation, stripped from the forwarded requests.
+	basicAuth *BasicAuthConfig
+
+	proxy *httputil.ReverseProxy
+}
+
+func newTryItOutProxy(c *Config, specHosts func(c *echo.Context) []string) *tryItOutProxy {
+	config := c.TryItOutProxy
+	if config == nil {
+		return nil
+	}
+
+	p := &tryItOutProxy{
+		allowedHosts:  config.AllowedHosts,
+		deniedHeaders: []string{"Cookie"},
+		timeout:       config.Timeout,
+		maxBodySize:   config.MaxBodySize,
+		specHosts:     specHosts,
+		basicAuth:     c.BasicAuth,
+	}
+	for _, header := range config.AllowedHeaders {
+		p.allowedHeaders = append(p.allowedHeaders, http.CanonicalHeaderKey(header))
+	}
+	if config.DeniedHeaders != nil {
+		p.deniedHeaders = make([]string, 0, len(config.DeniedHeaders))
+		for _, header := range config.DeniedHeaders {
+			p.deniedHeaders = append(p.deniedHeaders, http.CanonicalHeaderKey(header))
+		}
+	}
+	if p.timeout <= 0 {
+		p.timeout = 30 * time.Second
+	}
+	if p.maxBodySize <= 0 {
+		p.maxBodySize = 10 << 20
+	}
+
+	p.proxy = &httputil.ReverseProxy{
+		Rewrite:        p.rewrite,
+		ModifyResponse: p.modifyResponse,
+		ErrorHandler:   p.errorHandler,
+	}
+
+	return p
+}
+
+// serve forwards the request to the API url encoded in target, <scheme>/<host>/<path>.
+func (p *tryItOutProxy) serve(c *echo.Context, target string) error {
+	scheme, rest, _ := strings.Cut(target, "/")
+	host, path, _ := strings.Cut(rest, "/")
+	if (scheme != "http" && scheme != "https") || host == "" {
+		return c.String(http.StatusBadRequest, http.StatusText(http.StatusBadRequest))
+	}
+	if !p.allowed(c, host) {
+		return c.String(http.StatusForbidden, http.StatusText(http.StatusForbidden))
+	}
+	if c.Request().ContentLength > p.maxBodySize {
+		return c.String(http.StatusRequestEntityTooLarge, http.StatusText(http.StatusRequestEntityTooLarge))
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request().Context(), p.timeout)
+	defer cancel()
+
+	r := c.Request().Clone(ctx)
+	r.URL = &url.URL{Scheme: scheme, Host: host, Path: "/" + path, RawQuery: c.Request().URL.RawQuery}
+	if r.Body != nil {
+		r.Body = http.MaxBytesReader(c.Response(), r.Body, p.maxBodySize)
+	}
+
+	p.proxy.ServeHTTP(c.Response(), r)
+
+	return nil
+}
+
+// hosts returns the hosts requests may be forwarded to, given the hosts of the API
+// definitions of the handler.
+func (p *tryItOutProxy) hosts(specHosts []string) []string {
+	if len(p.allowedHosts) > 0 {
+		return p.allowedHosts
+	}
+
+	return specHosts
+}
+
+// allowed reports whether requests may be forwarded to host.
+func (p *tryItOutProxy) allowed(c *echo.Context, host string) bool {
+	return slices.ContainsFunc(p.hosts(p.specHosts(c)), func(allowed string) bool {
+		return strings.EqualFold(allowed, host)
+	})
+}
+
+func (p *tryItOutProxy) rewrite(r *httputil.ProxyRequest) {
+	r.Out.URL = r.In.URL
+	r.Out.Host = ""
+
+	for name := range r.Out.Header {
+		if slices.Contains(p.deniedHeaders, name) ||
+			(len(p.allowedHeaders) > 0 && !slices.Contains(p.allowedHeaders, name)) {
+			r.Out.Header.Del(name)
+		}
+	}
+
+	// Browsers send the credentials of the documentation with every request to its origin,
+	// which the API must not receive.
+	if p.basicAuth != nil {
+		if username, password, ok := r.Out.BasicAuth(); ok &&
+			secureCompare(username, p.basicAuth.Username)&secureCompare(password, p.basicAuth.Password) == 1 {
+			r.Out.Header.Del("Authorization")
+		}
+	}
+}
+
+func (p *tryItOutProxy) modifyResponse(resp *http.Response) error {
+	if resp.ContentLength > p.maxBodySize {
+		return errBodyTooLarge
+	}
+
+	// The response is served from the origin of the documentation, so it must neither
+	// set its cookies nor run as one of its pages.
+	resp.Header.Del("Set-Cookie")
+	resp.Header.Set("X-Content-Type-Options", "nosniff")
+	resp.Header.Set("Content-Security-Policy", "sandbox")
+	resp.Body = &limitedBody{ReadCloser: resp.Body, remaining: p.maxBodySize}
+
+	return nil
+}
+
+func (p *tryItOutProxy) errorHandler(w http.ResponseWriter, _ *http.Request, err error) {
+	status := http.StatusBadGateway
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		status = http.StatusGatewayTimeout
+	case errors.As(err, &maxBytesErr):
+		status = http.StatusRequestEntityTooLarge
+	}
+
+	http.Error(w, http.StatusText(status), status)
+}
+
+// limitedBody fails once more than remaining bytes are read.
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining < 0 {
+		return 0, errBodyTooLarge
+	}
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+
+	n, err := b.ReadCloser.Read(p)
+	if b.remaining -= int64(n); b.remaining < 0 {
+		// Drop the byte read past the limit.
+		return n - 1, errBodyTooLarge
+	}
+
+	return n, err
+}
+
+// specHosts returns the hosts of the API definition: host for Swagger 2.0, the hosts of
+// the absolute server urls for OpenAPI 3.
+func specHosts(doc []byte) ([]string, error) {
+	var spec struct {
+		Host    string `json:"host"`
+		Servers []struct {
+			URL string `json:"url"`
+		} `json:"servers"`
+	}
+	if err := json.Unmarshal(doc, &spec); err != nil {
+		return nil, fmt.Errorf("echoSwagger: reading the hosts of the API definition: %w", err)
+	}
+
+	var hosts []string
+	if spec.Host != "" {
+		hosts = append(hosts, spec.Host)
+	}
+	for _, server := range spec.Servers {
+		if u, err := url.Parse(server.URL); err == nil && u.Host != "" {
+			hosts = append(hosts, u.Host)
+		}
+	}
+
+	return hosts, nil
+}
+
+// providerHosts returns the hosts of the API definitions of the given instances, read from
+// provider.
+func providerHosts(c *echo.Context, provider DocProvider, instanceNames []string) []string {
+	var hosts []string
+	for _, instanceName := range instanceNames {
+		doc, err := provider.ReadDoc(c, instanceName)
+		if err != nil {
+			continue
+		}
+		if docHosts, err := specHosts(doc); err == nil {
+			hosts = append(hosts, docHosts...)
+		}
+	}
+
+	return hosts
+}
@@ -0,0 +1,215 @@
+package echoSwagger
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTryItOutProxyOption(t *testing.T) {
+	var cfg Config
+	expected := &ProxyConfig{AllowedHosts: []string{"api.example.com"}}
+	TryItOutProxy(expected)(&cfg)
+	assert.Equal(t, expected, cfg.TryItOutProxy)
+}
+
+func TestSpecHosts(t *testing.T) {
+	hosts, err := specHosts([]byte(`{"swagger": "2.0", "host": "petstore.swagger.io"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"petstore.swagger.io"}, hosts)
+
+	hosts, err = specHosts([]byte(`{"openapi": "3.0.3", "servers": [{"url": "https://api.example.com/v1"}, {"url": "/v2"}, {"url": "http://localhost:8080"}]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"api.example.com", "localhost:8080"}, hosts)
+
+	_, err = specHosts([]byte(`swagger: "2.0"`))
+	assert.Error(t, err)
+}
+
+func newUpstream(t *testing.T) (*httptest.Server, string) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/slow":
+			time.Sleep(200 * time.Millisecond)
+		case "/large":
+			w.Header().Set("Content-Length", "2048")
+			_, _ = w.Write(make([]byte, 2048))
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		http.SetCookie(w, &http.Cookie{Name: "upstream", Value: "1"})
+		w.Header().Set("X-Method", r.Method)
+		w.Header().Set("X-Tenant", r.Header.Get("X-Tenant"))
+		w.Header().Set("X-Cookie", r.Header.Get("Cookie"))
+		w.Header().Set("X-Authorization", r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(r.URL.RequestURI() + " " + string(body)))
+	}))
+	t.Cleanup(upstream.Close)
+
+	u, err := url.Parse(upstream.URL)
+	assert.NoError(t, err)
+
+	return upstream, u.Host
+}
+
+func proxyRequest(router *echo.Echo, method, target, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, target, strings.NewReader(body))
+	r.Header.Set("X-Tenant", "acme")
+	r.AddCookie(&http.Cookie{Name: "session", Value: "secret"})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	return w
+}
+
+func TestTryItOutProxyHandler(t *testing.T) {
+	_, host := newUpstream(t)
+
+	for _, wrap := range []func(options ...func(*Config)) echo.HandlerFunc{EchoWrapHandler, EchoWrapHandlerV3} {
+		router := echo.New()
+		router.Any("/swagger/*", wrap(TryItOutProxy(&ProxyConfig{
+			AllowedHosts: []string{host},
+			Timeout:      50 * time.Millisecond,
+			MaxBodySize:  1024,
+		})))
+
+		w1 := proxyRequest(router, http.MethodPost, "/swagger/proxy/http/"+host+"/pets?limit=10", `{"name":"rex"}`)
+		assert.Equal(t, http.StatusCreated, w1.Code)
+		assert.Equal(t, `/pets?limit=10 {"name":"rex"}`, w1.Body.String())
+		assert.Equal(t, http.MethodPost, w1.Header().Get("X-Method"))
+		assert.Equal(t, "acme", w1.Header().Get("X-Tenant"))
+		assert.Empty(t, w1.Header().Get("X-Cookie"))
+		assert.Empty(t, w1.Header().Get("Set-Cookie"))
+		assert.Equal(t, "nosniff", w1.Header().Get("X-Content-Type-Options"))
+		assert.Equal(t, "sandbox", w1.Header().Get("Content-Security-Policy"))
+
+		assert.Equal(t, http.StatusForbidden, proxyRequest(router, http.MethodGet, "/swagger/proxy/http/evil.example.com/pets", "").Code)
+		assert.Equal(t, http.StatusBadRequest, proxyRequest(router, http.MethodGet, "/swagger/proxy/file/"+host+"/etc/passwd", "").Code)
+		assert.Equal(t, http.StatusGatewayTimeout, proxyRequest(router, http.MethodGet, "/swagger/proxy/http/"+host+"/slow", "").Code)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, proxyRequest(router, http.MethodPost, "/swagger/proxy/http/"+host+"/pets", strings.Repeat("a", 2048)).Code)
+		assert.Equal(t, http.StatusBadGateway, proxyRequest(router, http.MethodGet, "/swagger/proxy/http/"+host+"/large", "").Code)
+
+		// Every other path still only answers GET requests.
+		assert.Equal(t, http.StatusMethodNotAllowed, performRequest(http.MethodPost, "/swagger/index.html", router).Code)
+
+		w2 := performRequest(http.MethodGet, "/swagger/index.html", router)
+		assert.Contains(t, w2.Body.String(), `<script src="./interceptors.js"> </script>`)
+		assert.Contains(t, w2.Body.String(), `requestInterceptor: window.echoSwaggerInterceptors.request,`)
+
+		w3 := performRequest(http.MethodGet, "/swagger/interceptors.js", router)
+		assert.Equal(t, http.StatusOK, w3.Code)
+		assert.Contains(t, w3.Body.String(), `var proxy = true;`)
+		assert.Contains(t, w3.Body.String(), `request.url = new URL("proxy/" + url.protocol.slice(0, -1)`)
+	}
+}
+
+func TestTryItOutProxyHeadersAndSpecHosts(t *testing.T) {
+	_, host := newUpstream(t)
+
+	router := echo.New()
+	router.Any("/swagger/*", EchoWrapHandler(
+		Provider(StaticDoc([]byte(`{"swagger": "2.0", "host": "`+host+`", "paths": {}}`))),
+		TryItOutProxy(&ProxyConfig{
+			AllowedHeaders: []string{"cookie", "content-type"},
+			DeniedHeaders:  []string{},
+		}),
+	))
+
+	w := proxyRequest(router, http.MethodPut, "/swagger/proxy/http/"+host+"/pets/1", "")
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, http.MethodPut, w.Header().Get("X-Method"))
+	assert.Empty(t, w.Header().Get("X-Tenant"))
+	assert.Equal(t, "session=secret", w.Header().Get("X-Cookie"))
+
+	assert.Equal(t, http.StatusForbidden, proxyRequest(router, http.MethodGet, "/swagger/proxy/http/localhost:1/pets", "").Code)
+
+	// The proxy is disabled by default.
+	router = echo.New()
+	router.Any("/swagger/*", EchoWrapHandler())
+	assert.Equal(t, http.StatusMethodNotAllowed, proxyRequest(router, http.MethodPost, "/swagger/proxy/http/"+host+"/pets", "").Code)
+	assert.Equal(t, http.StatusNotFound, performRequest(http.MethodGet, "/swagger/interceptors.js", router).Code)
+}
+
+func TestTryItOutProxyDynamicHost(t *testing.T) {
+	_, internal := newUpstream(t)
+
+	router := echo.New()
+	router.Any("/swagger/*", EchoWrapHandler(
+		Provider(StaticDoc([]byte(`{"swagger": "2.0", "host": "api.example.com", "paths": {}}`))),
+		DynamicHost(true),
+		TryItOutProxy(&ProxyConfig{}),
+	))
+
+	// The served definition follows the request, the allowlist does not.
+	for _, header := range []string{"X-Forwarded-Host", "Host"} {
+		r := httptest.NewRequest(http.MethodGet, "/swagger/proxy/http/"+internal+"/pets", nil)
+		if header == "Host" {
+			r.Host = internal
+		} else {
+			r.Header.Set(header, internal)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		assert.Equal(t, http.StatusForbidden, w.Code, header)
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/swagger/doc.json", nil)
+	r.Header.Set("X-Forwarded-Host", internal)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Contains(t, w.Body.String(), `"host":"`+internal+`"`)
+}
+
+func TestTryItOutProxyAuthorization(t *testing.T) {
+	_, host := newUpstream(t)
+
+	router := echo.New()
+	router.Any("/swagger/*", EchoWrapHandler(
+		BasicAuth("admin", "secret"),
+		TryItOutProxy(&ProxyConfig{AllowedHosts: []string{host}}),
+	))
+
+	r := httptest.NewRequest(http.MethodGet, "/swagger/proxy/http/"+host+"/pets", nil)
+	r.SetBasicAuth("admin", "secret")
+	w1 := httptest.NewRecorder()
+	router.ServeHTTP(w1, r)
+	assert.Equal(t, http.StatusCreated, w1.Code)
+	assert.Empty(t, w1.Header().Get("X-Authorization"))
+
+	// Other credentials are meant for the API.
+	router = echo.New()
+	router.Any("/swagger/*", EchoWrapHandler(TryItOutProxy(&ProxyConfig{AllowedHosts: []string{host}})))
+
+	r = httptest.NewRequest(http.MethodGet, "/swagger/proxy/http/"+host+"/pets", nil)
+	r.Header.Set("Authorization", "Bearer token")
+	w2 := httptest.NewRecorder()
+	router.ServeHTTP(w2, r)
+	assert.Equal(t, http.StatusCreated, w2.Code)
+	assert.Equal(t, "Bearer token", w2.Header().Get("X-Authorization"))
+}
+
+func TestTryItOutProxyOAuth(t *testing.T) {
+	router := echo.New()
+	router.Any("/swagger/*", EchoWrapHandler(
+		Provider(StaticDoc([]byte(`{"swagger": "2.0", "host": "api.example.com", "paths": {}}`))),
+		OAuth(&OAuthConfig{ClientId: "docs"}),
+		TryItOutProxy(&ProxyConfig{}),
+	))
+
+	// Only the requests to the API hosts are rewritten, and never the token requests of
+	// the Authorize dialog, so that they reach the authorization server directly.
+	script := performRequest(http.MethodGet, "/swagger/interceptors.js", router).Body.String()
+	assert.Contains(t, script, `var proxyHosts = ["api.example.com"];`)
+	assert.Contains(t, script, `if (proxy && !sameOrigin && proxyHosts.indexOf(url.host) >= 0 && !tokenRequest(request) &&`)
+	assert.Contains(t, script, `return typeof request.body === "string" && /(^|&)grant_type=/.test(request.body);`)
+
+	assert.Equal(t, http.StatusForbidden, proxyRequest(router, http.MethodPost, "/swagger/proxy/https/idp.example.org/token", "grant_type=authorization_code").Code)
+}
@@ -0,0 +1,570 @@
+package echoSwagger
+
+import (
+	"bytes"
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v5"
+)
+
+// PageRenderer renders the documentation page served as index.html in place of Swagger UI.
+type PageRenderer interface {
+	// Render writes the page documenting the API definitions listed in specs. With
+	// SecurityHeaders, nonce is the nonce of the Content-Security-Policy of the page, which
+	// its scripts and styles must carry; it is empty otherwise.
+	Render(c *echo.Context, specs []SpecURL, nonce string) error
+}
+
+// primarySpecURL returns the url of the primary spec, or of the first one if none is primary.
+func primarySpecURL(specs []SpecURL) string {
+	for _, spec := range specs {
+		if spec.Primary {
+			return spec.URL
+		}
+	}
+
+	if len(specs) == 0 {
+		return "doc.json"
+	}
+
+	return specs[0].URL
+}
+
+// isYAMLURL reports whether url points to a YAML document.
+func isYAMLURL(url string) bool {
+	return strings.HasSuffix(url, ".yaml") || strings.HasSuffix(url, ".yml")
+}
+
+// renderPage executes tmpl with data and writes the result as an HTML page.
+func renderPage(c *echo.Context, tmpl *template.Template, data any) error {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return c.String(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.HTMLBlob(http.StatusOK, buf.Bytes())
+}
+
+// jsonJS encodes v as a JavaScript literal. json.Marshal escapes <, > and &, so that the
+// result can be embedded in a script element.
+func jsonJS(v any) (template.JS, error) {
+	b, err := json.Marshal(v)
+	return template.JS(b), err
+}
+
+// RedocConfig stores configuration for the Redoc renderer. See
+// https://redocly.com/docs/redoc/config for further details.
+type RedocConfig struct {
+	// The page title. Default is `API Reference`.
+	Title string
+
+	// The url pointing to API definition. Default is the primary spec url of the handler.
+	SpecURL string
+
+	// The url of the Redoc standalone bundle.
+	ScriptURL string
+
+	HideDownloadButton bool
+	DisableSearch      bool
+
+	// The responses expanded by default, e.g. `200,201` or `all`.
+	ExpandResponses string
+}
+
+// RedocTitle sets the page title.
+func RedocTitle(title string) func(*RedocConfig) {
+	return func(c *RedocConfig) {
+		c.Title = title
+	}
+}
+
+// RedocSpecURL sets the url pointing to API definition.
+func RedocSpecURL(url string) func(*RedocConfig) {
+	return func(c *RedocConfig) {
+		c.SpecURL = url
+	}
+}
+
+// RedocScriptURL sets the url of the Redoc standalone bundle.
+func RedocScriptURL(url string) func(*RedocConfig) {
+	return func(c *RedocConfig) {
+		c.ScriptURL = url
+	}
+}
+
+// RedocHideDownloadButton hides the button downloading the API definition.
+func RedocHideDownloadButton(hide bool) func(*RedocConfig) {
+	return func(c *RedocConfig) {
+		c.HideDownloadButton = hide
+	}
+}
+
+// RedocDisableSearch hides the search box.
+func RedocDisableSearch(disable bool) func(*RedocConfig) {
+	return func(c *RedocConfig) {
+		c.DisableSearch = disable
+	}
+}
+
+// RedocExpandResponses sets the responses expanded by default, e.g. `200,201` or `all`.
+func RedocExpandResponses(expandResponses string) func(*RedocConfig) {
+	return func(c *RedocConfig) {
+		c.ExpandResponses = expandResponses
+	}
+}
+
+type redocRenderer struct {
+	config RedocConfig
+}
+
+// Redoc renders the API definition with Redoc.
+func Redoc(options ...func(*RedocConfig)) PageRenderer {
+	config := RedocConfig{
+		Title:     "API Reference",
+		ScriptURL: "https://cdn.redoc.ly/redoc/latest/bundles/redoc.standalone.js",
+	}
+
+	for _, fn := range options {
+		fn(&config)
+	}
+
+	return &redocRenderer{config: config}
+}
+
+func (r *redocRenderer) Render(c *echo.Context, specs []SpecURL, nonce string) error {
+	specURL := r.config.SpecURL
+	if specURL == "" {
+		specURL = primarySpecURL(specs)
+	}
+
+	options, err := jsonJS(map[string]any{
+		"hideDownloadButton": r.config.HideDownloadButton,
+		"disableSearch":      r.config.DisableSearch,
+		"expandResponses":    r.config.ExpandResponses,
+	})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, err.Error())
+	}
+
+	return renderPage(c, redocTemplate, map[string]any{
+		"Config":  r.config,
+		"SpecURL": specURL,
+		"Options": options,
+		"Nonce":   nonce,
+	})
+}
+
+var redocTemplate = template.Must(template.New("redoc.html").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="UTF-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+  <title>{{.Config.Title}}</title>
+  <style{{with .Nonce}} nonce="{{.}}"{{end}}>
+    body {
+      margin: 0;
+      padding: 0;
+    }
+  </style>
+</head>
+<body>
+<div id="redoc-container"></div>
+<script src="{{.Config.ScriptURL}}"{{with .Nonce}} nonce="{{.}}"{{end}}></script>
+<script{{with .Nonce}} nonce="{{.}}"{{end}}>
+  Redoc.init({{.SpecURL}}, {{.Options}}, document.getElementById("redoc-container"))
+</script>
+</body>
+</html>
+`))
+
+// RapiDocConfig stores configuration for the RapiDoc renderer. See
+// https://rapidocweb.com/api.html for further details.
+type RapiDocConfig struct {
+	// The page title. Default is `API Reference`.
+	Title string
+
+	// The url pointing to API definition. Default is the primary spec url of the handler.
+	SpecURL string
+
+	// The url of the RapiDoc bundle.
+	ScriptURL string
+
+	// The color theme, `light` or `dark`. Default is `light`.
+	Theme string
+
+	// The layout, `read`, `view` or `focused`. Default is `read`.
+	RenderStyle string
+
+	ShowHeader bool
+	AllowTry   bool
+}
+
+// RapiDocTitle sets the page title.
+func RapiDocTitle(title string) func(*RapiDocConfig) {
+	return func(c *RapiDocConfig) {
+		c.Title = title
+	}
+}
+
+// RapiDocSpecURL sets the url pointing to API definition.
+func RapiDocSpecURL(url string) func(*RapiDocConfig) {
+	return func(c *RapiDocConfig) {
+		c.SpecURL = url
+	}
+}
+
+// RapiDocScriptURL sets the url of the RapiDoc bundle.
+func RapiDocScriptURL(url string) func(*RapiDocConfig) {
+	return func(c *RapiDocConfig) {
+		c.ScriptURL = url
+	}
+}
+
+// RapiDocTheme light, dark.
+func RapiDocTheme(theme string) func(*RapiDocConfig) {
+	return func(c *RapiDocConfig) {
+		c.Theme = theme
+	}
+}
+
+// RapiDocRenderStyle read, view, focused.
+func RapiDocRenderStyle(renderStyle string) func(*RapiDocConfig) {
+	return func(c *RapiDocConfig) {
+		c.RenderStyle = renderStyle
+	}
+}
+
+// RapiDocShowHeader shows the header holding the spec url input. Defaults to false.
+func RapiDocShowHeader(showHeader bool) func(*RapiDocConfig) {
+	return func(c *RapiDocConfig) {
+		c.ShowHeader = showHeader
+	}
+}
+
+// RapiDocAllowTry enables the "Try" feature. Defaults to true.
+func RapiDocAllowTry(allowTry bool) func(*RapiDocConfig) {
+	return func(c *RapiDocConfig) {
+		c.AllowTry = allowTry
+	}
+}
+
+type rapiDocRenderer struct {
+	config RapiDocConfig
+}
+
+// RapiDoc renders the API definition with RapiDoc.
+func RapiDoc(options ...func(*RapiDocConfig)) PageRenderer {
+	config := RapiDocConfig{
+		Title:       "API Reference",
+		ScriptURL:   "https://unpkg.com/rapidoc/dist/rapidoc-min.js",
+		Theme:       "light",
+		RenderStyle: "read",
+		AllowTry:    true,
+	}
+
+	for _, fn := range options {
+		fn(&config)
+	}
+
+	return &rapiDocRenderer{config: config}
+}
+
+func (r *rapiDocRenderer) Render(c *echo.Context, specs []SpecURL, nonce string) error {
+	specURL := r.config.SpecURL
+	if specURL == "" {
+		specURL = primarySpecURL(specs)
+	}
+
+	return renderPage(c, rapiDocTemplate, map[string]any{
+		"Config":  r.config,
+		"SpecURL": specURL,
+		"Nonce":   nonce,
+	})
+}
+
+var rapiDocTemplate = template.Must(template.New("rapidoc.html").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="UTF-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+  <title>{{.Config.Title}}</title>
+  <script type="module" src="{{.Config.ScriptURL}}"{{with .Nonce}} nonce="{{.}}"{{end}}></script>
+</head>
+<body>
+<rapi-doc
+  spec-url="{{.SpecURL}}"
+  theme="{{.Config.Theme}}"
+  render-style="{{.Config.RenderStyle}}"
+  show-header="{{.Config.ShowHeader}}"
+  allow-try="{{.Config.AllowTry}}"
+></rapi-doc>
+</body>
+</html>
+`))
+
+// ScalarConfig stores configuration for the Scalar API reference renderer. See
+// https://github.com/scalar/scalar for further details.
+type ScalarConfig struct {
+	// The page title. Default is `API Reference`.
+	Title string
+
+	// The url pointing to API definition. Default is every spec url of the handler.
+	SpecURL string
+
+	// The url of the Scalar API reference bundle.
+	ScriptURL string
+
+	// The color theme, e.g. `default`, `moon` or `purple`. Default is `default`.
+	Theme string
+
+	// The layout, `modern` or `classic`. Default is `modern`.
+	Layout string
+
+	DarkMode   bool
+	HideModels bool
+}
+
+// ScalarTitle sets the page title.
+func ScalarTitle(title string) func(*ScalarConfig) {
+	return func(c *ScalarConfig) {
+		c.Title = title
+	}
+}
+
+// ScalarSpecURL sets the url pointing to API definition.
+func ScalarSpecURL(url string) func(*ScalarConfig) {
+	return func(c *ScalarConfig) {
+		c.SpecURL = url
+	}
+}
+
+// ScalarScriptURL sets the url of the Scalar API reference bundle.
+func ScalarScriptURL(url string) func(*ScalarConfig) {
+	return func(c *ScalarConfig) {
+		c.ScriptURL = url
+	}
+}
+
+// ScalarTheme sets the color theme, e.g. `default`, `moon` or `purple`.
+func ScalarTheme(theme string) func(*ScalarConfig) {
+	return func(c *ScalarConfig) {
+		c.Theme = theme
+	}
+}
+
+// ScalarLayout modern, classic.
+func ScalarLayout(layout string) func(*ScalarConfig) {
+	return func(c *ScalarConfig) {
+		c.Layout = layout
+	}
+}
+
+// ScalarDarkMode enables the dark mode. Defaults to false.
+func ScalarDarkMode(darkMode bool) func(*ScalarConfig) {
+	return func(c *ScalarConfig) {
+		c.DarkMode = darkMode
+	}
+}
+
+// ScalarHideModels hides the models section. Defaults to false.
+func ScalarHideModels(hideModels bool) func(*ScalarConfig) {
+	return func(c *ScalarConfig) {
+		c.HideModels = hideModels
+	}
+}
+
+type scalarRenderer struct {
+	config ScalarConfig
+}
+
+// Scalar renders the API definition with the Scalar API reference.
+func Scalar(options ...func(*ScalarConfig)) PageRenderer {
+	config := ScalarConfig{
+		Title:     "API Reference",
+		ScriptURL: "https://cdn.jsdelivr.net/npm/@scalar/api-reference",
+		Theme:     "default",
+		Layout:    "modern",
+	}
+
+	for _, fn := range options {
+		fn(&config)
+	}
+
+	return &scalarRenderer{config: config}
+}
+
+func (r *scalarRenderer) Render(c *echo.Context, specs []SpecURL, nonce string) error {
+	type source struct {
+		Title   string `json:"title"`
+		URL     string `json:"url"`
+		Default bool   `json:"default,omitempty"`
+	}
+
+	var sources []source
+	if r.config.SpecURL != "" {
+		sources = []source{{Title: r.config.Title, URL: r.config.SpecURL}}
+	} else {
+		for _, spec := range specs {
+			// Scalar renders JSON and YAML alike, so only list the JSON documents.
+			if !isYAMLURL(spec.URL) {
+				sources = append(sources, source{Title: spec.Name, URL: spec.URL, Default: spec.Primary})
+			}
+		}
+	}
+
+	configuration, err := jsonJS(map[string]any{
+		"sources":    sources,
+		"theme":      r.config.Theme,
+		"layout":     r.config.Layout,
+		"darkMode":   r.config.DarkMode,
+		"hideModels": r.config.HideModels,
+	})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, err.Error())
+	}
+
+	return renderPage(c, scalarTemplate, map[string]any{
+		"Config":        r.config,
+		"Configuration": configuration,
+		"Nonce":         nonce,
+	})
+}
+
+var scalarTemplate = template.Must(template.New("scalar.html").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="UTF-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+  <title>{{.Config.Title}}</title>
+</head>
+<body>
+<div id="app"></div>
+<script src="{{.Config.ScriptURL}}"{{with .Nonce}} nonce="{{.}}"{{end}}></script>
+<script{{with .Nonce}} nonce="{{.}}"{{end}}>
+  Scalar.createApiReference("#app", {{.Configuration}})
+</script>
+</body>
+</html>
+`))
+
+// ElementsConfig stores configuration for the Stoplight Elements renderer. See
+// https://docs.stoplight.io/docs/elements for further details.
+type ElementsConfig struct {
+	// The page title. Default is `API Reference`.
+	Title string
+
+	// The url pointing to API definition. Default is the primary spec url of the handler.
+	SpecURL string
+
+	// The urls of the Elements web components bundle and stylesheet.
+	ScriptURL     string
+	StylesheetURL string
+
+	// The layout, `sidebar`, `responsive` or `stacked`. Default is `sidebar`.
+	Layout string
+
+	// The router, `hash`, `history` or `memory`. Default is `hash`.
+	Router string
+
+	HideTryIt bool
+}
+
+// ElementsTitle sets the page title.
+func ElementsTitle(title string) func(*ElementsConfig) {
+	return func(c *ElementsConfig) {
+		c.Title = title
+	}
+}
+
+// ElementsSpecURL sets the url pointing to API definition.
+func ElementsSpecURL(url string) func(*ElementsConfig) {
+	return func(c *ElementsConfig) {
+		c.SpecURL = url
+	}
+}
+
+// ElementsAssetURLs sets the urls of the Elements web components bundle and stylesheet.
+func ElementsAssetURLs(scriptURL, stylesheetURL string) func(*ElementsConfig) {
+	return func(c *ElementsConfig) {
+		c.ScriptURL = scriptURL
+		c.StylesheetURL = stylesheetURL
+	}
+}
+
+// ElementsLayout sidebar, responsive, stacked.
+func ElementsLayout(layout string) func(*ElementsConfig) {
+	return func(c *ElementsConfig) {
+		c.Layout = layout
+	}
+}
+
+// ElementsRouter hash, history, memory.
+func ElementsRouter(router string) func(*ElementsConfig) {
+	return func(c *ElementsConfig) {
+		c.Router = router
+	}
+}
+
+// ElementsHideTryIt hides the "Try It" panel. Defaults to false.
+func ElementsHideTryIt(hideTryIt bool) func(*ElementsConfig) {
+	return func(c *ElementsConfig) {
+		c.HideTryIt = hideTryIt
+	}
+}
+
+type elementsRenderer struct {
+	config ElementsConfig
+}
+
+// Elements renders the API definition with Stoplight Elements.
+func Elements(options ...func(*ElementsConfig)) PageRenderer {
+	config := ElementsConfig{
+		Title:         "API Reference",
+		ScriptURL:     "https://unpkg.com/@stoplight/elements/web-components.min.js",
+		StylesheetURL: "https://unpkg.com/@stoplight/elements/styles.min.css",
+		Layout:        "sidebar",
+		Router:        "hash",
+	}
+
+	for _, fn := range options {
+		fn(&config)
+	}
+
+	return &elementsRenderer{config: config}
+}
+
+func (r *elementsRenderer) Render(c *echo.Context, specs []SpecURL, nonce string) error {
+	specURL := r.config.SpecURL
+	if specURL == "" {
+		specURL = primarySpecURL(specs)
+	}
+
+	return renderPage(c, elementsTemplate, map[string]any{
+		"Config":  r.config,
+		"SpecURL": specURL,
+		"Nonce":   nonce,
+	})
+}
+
+var elementsTemplate = template.Must(template.New("elements.html").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="UTF-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+  <title>{{.Config.Title}}</title>
+  <script src="{{.Config.ScriptURL}}"{{with .Nonce}} nonce="{{.}}"{{end}}></script>
+  <link rel="stylesheet" href="{{.Config.StylesheetURL}}"{{with .Nonce}} nonce="{{.}}"{{end}}>
+</head>
+<body>
+<elements-api
+  apiDescriptionUrl="{{.SpecURL}}"
+  router="{{.Config.Router}}"
+  layout="{{.Config.Layout}}"
+  hideTryIt="{{.Config.HideTryIt}}"
+></elements-api>
+</body>
+</html>
+`))
@@ -0,0 +1,105 @@
+package echoSwagger
+
+import (
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRendererOption(t *testing.T) {
+	var cfg Config
+	Renderer(Redoc())(&cfg)
+	assert.NotNil(t, cfg.Renderer)
+}
+
+func TestRenderersSideBySide(t *testing.T) {
+	provider := Provider(StaticDoc([]byte((&mockedSwag{}).ReadDoc())))
+
+	router := echo.New()
+	router.GET("/swagger/*", EchoWrapHandler(provider))
+	router.GET("/redoc/*", EchoWrapHandler(provider, Renderer(Redoc(RedocTitle("Public API"), RedocHideDownloadButton(true)))))
+	router.GET("/rapidoc/*", EchoWrapHandlerV3(provider, Renderer(RapiDoc(RapiDocTheme("dark")))))
+	router.GET("/scalar/*", EchoWrapHandler(provider, Renderer(Scalar(ScalarLayout("classic")))))
+	router.GET("/elements/*", EchoWrapHandler(provider, Renderer(Elements(ElementsSpecURL("../swagger/doc.json")))))
+
+	w1 := performRequest(http.MethodGet, "/redoc/index.html", router)
+	assert.Equal(t, http.StatusOK, w1.Code)
+	assert.Equal(t, "text/html; charset=utf-8", w1.Header().Get("Content-Type"))
+	assert.Contains(t, w1.Body.String(), `<title>Public API</title>`)
+	assert.Contains(t, w1.Body.String(), `<script src="https://cdn.redoc.ly/redoc/latest/bundles/redoc.standalone.js"></script>`)
+	assert.Contains(t, w1.Body.String(), `Redoc.init("doc.json", {"disableSearch":false,"expandResponses":"","hideDownloadButton":true}, `)
+
+	w2 := performRequest(http.MethodGet, "/rapidoc/index.html", router)
+	assert.Equal(t, http.StatusOK, w2.Code)
+	assert.Contains(t, w2.Body.String(), `spec-url="doc.json"`)
+	assert.Contains(t, w2.Body.String(), `theme="dark"`)
+	assert.Contains(t, w2.Body.String(), `allow-try="true"`)
+
+	w3 := performRequest(http.MethodGet, "/scalar/index.html", router)
+	assert.Equal(t, http.StatusOK, w3.Code)
+	assert.Contains(t, w3.Body.String(), `Scalar.createApiReference("#app", {"darkMode":false,"hideModels":false,"layout":"classic","sources":[{"title":"doc.json","url":"doc.json"}],"theme":"default"})`)
+
+	w4 := performRequest(http.MethodGet, "/elements/index.html", router)
+	assert.Equal(t, http.StatusOK, w4.Code)
+	assert.Contains(t, w4.Body.String(), `apiDescriptionUrl="../swagger/doc.json"`)
+	assert.Contains(t, w4.Body.String(), `layout="sidebar"`)
+
+	// Every mount keeps serving the API definition.
+	for _, prefix := range []string{"/swagger", "/redoc", "/rapidoc", "/scalar", "/elements"} {
+		assert.Equal(t, http.StatusOK, performRequest(http.MethodGet, prefix+"/doc.json", router).Code, prefix)
+	}
+}
+
+func TestRendererEscaping(t *testing.T) {
+	router := echo.New()
+	router.GET("/*", EchoWrapHandler(Renderer(Redoc(
+		RedocTitle("</title><script>alert(1)</script>"),
+		RedocSpecURL(`doc.json");alert("1`),
+		RedocExpandResponses("</script>"),
+	))))
+
+	body := performRequest(http.MethodGet, "/index.html", router).Body.String()
+	assert.NotContains(t, body, "<script>alert(1)</script>")
+	assert.NotContains(t, body, `doc.json");alert("1`)
+	assert.Contains(t, body, `"expandResponses":"\u003c/script\u003e"`)
+}
+
+func TestRendererSecurityHeaders(t *testing.T) {
+	nonceRe := regexp.MustCompile(`script-src 'self' 'nonce-([^']+)'`)
+
+	for _, renderer := range []PageRenderer{Redoc(), RapiDoc(), Scalar(), Elements()} {
+		router := echo.New()
+		router.GET("/*", EchoWrapHandler(Renderer(renderer), SecurityHeaders(&SecurityHeadersConfig{})))
+
+		w := performRequest(http.MethodGet, "/index.html", router)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		matches := nonceRe.FindStringSubmatch(w.Header().Get("Content-Security-Policy"))
+		if !assert.Len(t, matches, 2) {
+			continue
+		}
+
+		// Every script and style of the page carries the nonce.
+		body := w.Body.String()
+		for _, tag := range regexp.MustCompile(`<(script|style|link rel="stylesheet")[^>]*>`).FindAllString(body, -1) {
+			assert.Contains(t, tag, ` nonce="`+matches[1]+`"`)
+		}
+	}
+
+	router := echo.New()
+	router.GET("/*", EchoWrapHandler(Renderer(Redoc())))
+
+	w := performRequest(http.MethodGet, "/index.html", router)
+	assert.Empty(t, w.Header().Get("Content-Security-Policy"))
+	assert.Contains(t, w.Body.String(), "<style>")
+	assert.NotContains(t, w.Body.String(), "nonce=")
+}
+
+func TestPrimarySpecURL(t *testing.T) {
+	assert.Equal(t, "doc.json", primarySpecURL(nil))
+	assert.Equal(t, "a.json", primarySpecURL([]SpecURL{{URL: "a.json"}, {URL: "b.json"}}))
+	assert.Equal(t, "b.json", primarySpecURL([]SpecURL{{URL: "a.json"}, {URL: "b.json", Primary: true}}))
+}
@@ -0,0 +1,99 @@
+package echoSwagger
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+
+	"github.com/labstack/echo/v5"
+)
+
+const (
+	headerXForwardedHost   = "X-Forwarded-Host"
+	headerXForwardedPrefix = "X-Forwarded-Prefix"
+)
+
+// dynamicHost wraps provider to rewrite the served API definition to the host the
+// request was made for.
+func dynamicHost(provider DocProvider) DocProvider {
+	return DocProviderFunc(func(c *echo.Context, instanceName string) ([]byte, error) {
+		doc, err := provider.ReadDoc(c, instanceName)
+		if err != nil {
+			return nil, err
+		}
+
+		return rewriteHost(c, doc)
+	})
+}
+
+// requestHost returns the host the request was made for, honoring X-Forwarded-Host.
+func requestHost(c *echo.Context) string {
+	if host := c.Request().Header.Get(headerXForwardedHost); host != "" {
+		host, _, _ = strings.Cut(host, ",")
+		return strings.TrimSpace(host)
+	}
+
+	return c.Request().Host
+}
+
+// requestPrefix returns the path prefix stripped by a reverse proxy, if any.
+func requestPrefix(c *echo.Context) string {
+	return strings.TrimSuffix(c.Request().Header.Get(headerXForwardedPrefix), "/")
+}
+
+// joinPrefix prepends the proxy prefix to path.
+func joinPrefix(prefix, path string) string {
+	if prefix == "" {
+		return path
+	}
+
+	if path = strings.TrimSuffix(path, "/"); path != "" && !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	return prefix + path
+}
+
+func rewriteHost(c *echo.Context, doc []byte) ([]byte, error) {
+	var spec map[string]any
+	if err := json.Unmarshal(doc, &spec); err != nil {
+		return nil, err
+	}
+
+	scheme, host, prefix := c.Scheme(), requestHost(c), requestPrefix(c)
+
+	if _, ok := spec["openapi"]; ok {
+		servers, _ := spec["servers"].([]any)
+		if len(servers) == 0 {
+			servers = []any{map[string]any{"url": "/"}}
+		}
+
+		for _, server := range servers {
+			server, ok := server.(map[string]any)
+			if !ok {
+				continue
+			}
+
+			path := "/"
+			if serverURL, ok := server["url"].(string); ok {
+				if u, err := url.Parse(serverURL); err == nil && u.Path != "" {
+					path = u.Path
+				}
+			}
+
+			server["url"] = (&url.URL{Scheme: scheme, Host: host, Path: joinPrefix(prefix, path)}).String()
+		}
+
+		spec["servers"] = servers
+	} else {
+		spec["host"] = host
+		spec["schemes"] = []string{scheme}
+
+		if prefix != "" {
+			basePath, _ := spec["basePath"].(string)
+			spec["basePath"] = joinPrefix(prefix, basePath)
+		}
+	}
+
+	return json.Marshal(spec)
+}
@@ -0,0 +1,77 @@
+package echoSwagger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDynamicHost(t *testing.T) {
+	var cfg Config
+	DynamicHost(true)(&cfg)
+	assert.True(t, cfg.DynamicHost)
+}
+
+func TestDynamicHostSwagger2(t *testing.T) {
+	router := echo.New()
+	router.GET("/swagger/*", EchoWrapHandler(DynamicHost(true), Provider(StaticDoc([]byte((&mockedSwag{}).ReadDoc())))))
+
+	r := httptest.NewRequest(http.MethodGet, "/swagger/doc.json", nil)
+	r.Host = "localhost:1323"
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"host":"localhost:1323"`)
+	assert.Contains(t, w.Body.String(), `"schemes":["http"]`)
+	assert.Contains(t, w.Body.String(), `"basePath":"/v2"`)
+
+	r = httptest.NewRequest(http.MethodGet, "/swagger/doc.yaml", nil)
+	r.Header.Set("X-Forwarded-Host", "api.example.com, proxy.internal")
+	r.Header.Set("X-Forwarded-Proto", "https")
+	r.Header.Set("X-Forwarded-Prefix", "/petstore/")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "host: api.example.com\n")
+	assert.Contains(t, w.Body.String(), "schemes:\n- https\n")
+	assert.Contains(t, w.Body.String(), "basePath: /petstore/v2\n")
+}
+
+func TestDynamicHostOpenAPI3(t *testing.T) {
+	doc := `{"openapi": "3.0.3", "servers": [{"url": "https://petstore.swagger.io/v2"}, {"url": "/v3"}], "paths": {}}`
+
+	router := echo.New()
+	router.GET("/swagger/*", EchoWrapHandlerV3(DynamicHost(true), Provider(StaticDoc([]byte(doc)))))
+
+	r := httptest.NewRequest(http.MethodGet, "/swagger/doc.json", nil)
+	r.Host = "staging.example.com"
+	r.Header.Set("X-Forwarded-Proto", "https")
+	r.Header.Set("X-Forwarded-Prefix", "/api")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"openapi": "3.0.3", "servers": [
+		{"url": "https://staging.example.com/api/v2"},
+		{"url": "https://staging.example.com/api/v3"}
+	], "paths": {}}`, w.Body.String())
+
+	router = echo.New()
+	router.GET("/swagger/*", EchoWrapHandlerV3(DynamicHost(true), Provider(StaticDoc([]byte(`{"openapi": "3.0.3"}`)))))
+
+	r = httptest.NewRequest(http.MethodGet, "/swagger/doc.json", nil)
+	r.Host = "localhost:1323"
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.JSONEq(t, `{"openapi": "3.0.3", "servers": [{"url": "http://localhost:1323/"}]}`, w.Body.String())
+}
+
+func TestJoinPrefix(t *testing.T) {
+	assert.Equal(t, "/v2", joinPrefix("", "/v2"))
+	assert.Equal(t, "/api/v2", joinPrefix("/api", "/v2"))
+	assert.Equal(t, "/api/v2", joinPrefix("/api", "v2/"))
+	assert.Equal(t, "/api", joinPrefix("/api", "/"))
+	assert.Equal(t, "/api", joinPrefix("/api", ""))
+}
@@ -0,0 +1,138 @@
+package echoSwagger
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/labstack/echo/v5"
+)
+
+// SecurityHeadersConfig stores the security headers sent with the documentation.
+type SecurityHeadersConfig struct {
+	// Directives of the Content-Security-Policy sent with the documentation page, by name,
+	// replacing the default ones, e.g. `"connect-src": "'self' https://api.example.com"`.
+	// An empty value removes the directive. The nonce of the page is always added to
+	// script-src and style-src.
+	CSPDirectives map[string]string
+
+	// The Referrer-Policy header. Default is `no-referrer`.
+	ReferrerPolicy string
+}
+
+// defaultCSPDirectives are the directives of the Content-Security-Policy, in the order they
+// are sent.
+var defaultCSPDirectives = []struct{ name, value string }{
+	{"default-src", "'self'"},
+	{"script-src", "'self'"},
+	{"style-src", "'self'"},
+	{"img-src", "'self' data:"},
+	{"font-src", "'self' data:"},
+	{"object-src", "'none'"},
+	{"base-uri", "'self'"},
+	{"frame-ancestors", "'self'"},
+}
+
+// securityHeaders sets the security headers of every response of a handler.
+type securityHeaders struct {
+	config *SecurityHeadersConfig
+
+	// The origin of Config.AssetsBaseURL, allowed to serve scripts and stylesheets.
+	assetsOrigin string
+}
+
+func newSecurityHeaders(config *Config) *securityHeaders {
+	if config.SecurityHeaders == nil {
+		return nil
+	}
+
+	s := &securityHeaders{config: config.SecurityHeaders}
+	if u, err := url.Parse(config.AssetsBaseURL); err == nil && u.Host != "" {
+		s.assetsOrigin = u.Scheme + "://" + u.Host
+	}
+
+	return s
+}
+
+// set sets X-Content-Type-Options and Referrer-Policy.
+func (s *securityHeaders) set(c *echo.Context) {
+	if s == nil {
+		return
+	}
+
+	referrerPolicy := s.config.ReferrerPolicy
+	if referrerPolicy == "" {
+		referrerPolicy = "no-referrer"
+	}
+
+	header := c.Response().Header()
+	header.Set("X-Content-Type-Options", "nosniff")
+	header.Set("Referrer-Policy", referrerPolicy)
+}
+
+// setPolicy sets the Content-Security-Policy of the documentation page and returns the
+// nonce its inline scripts and styles must carry.
+func (s *securityHeaders) setPolicy(c *echo.Context) (string, error) {
+	if s == nil {
+		return "", nil
+	}
+
+	nonce, err := newNonce()
+	if err != nil {
+		return "", err
+	}
+
+	c.Response().Header().Set("Content-Security-Policy", s.policy(nonce))
+
+	return nonce, nil
+}
+
+// policy returns the Content-Security-Policy allowing the given nonce.
+func (s *securityHeaders) policy(nonce string) string {
+	directives := make(map[string]string, len(defaultCSPDirectives))
+	names := make([]string, 0, len(defaultCSPDirectives))
+	for _, directive := range defaultCSPDirectives {
+		value := directive.value
+		if s.assetsOrigin != "" && (directive.name == "script-src" || directive.name == "style-src") {
+			value += " " + s.assetsOrigin
+		}
+		directives[directive.name] = value
+		names = append(names, directive.name)
+	}
+
+	var extra []string
+	for name, value := range s.config.CSPDirectives {
+		if _, ok := directives[name]; !ok {
+			extra = append(extra, name)
+		}
+		directives[name] = value
+	}
+	sort.Strings(extra)
+
+	var policy []string
+	for _, name := range append(names, extra...) {
+		value := directives[name]
+		if value == "" {
+			continue
+		}
+		switch name {
+		case "script-src", "style-src":
+			value += " 'nonce-" + nonce + "'"
+		}
+		policy = append(policy, name+" "+value)
+	}
+
+	return strings.Join(policy, "; ")
+}
+
+// newNonce returns a random Content-Security-Policy nonce.
+func newNonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,80 @@
+package echoSwagger
+
+import (
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecurityHeadersOption(t *testing.T) {
+	var cfg Config
+	expected := &SecurityHeadersConfig{ReferrerPolicy: "same-origin"}
+	SecurityHeaders(expected)(&cfg)
+	assert.Equal(t, expected, cfg.SecurityHeaders)
+}
+
+func TestSecurityHeadersPolicy(t *testing.T) {
+	s := newSecurityHeaders(newConfig(SecurityHeaders(&SecurityHeadersConfig{})))
+	assert.Equal(t, "default-src 'self'; script-src 'self' 'nonce-abc'; style-src 'self' 'nonce-abc'; img-src 'self' data:; "+
+		"font-src 'self' data:; object-src 'none'; base-uri 'self'; frame-ancestors 'self'", s.policy("abc"))
+
+	s = newSecurityHeaders(newConfig(
+		AssetsCDN("https://unpkg.com/swagger-ui-dist@5.17.14", nil),
+		SecurityHeaders(&SecurityHeadersConfig{CSPDirectives: map[string]string{
+			"connect-src":     "'self' https://api.example.com",
+			"frame-ancestors": "",
+			"img-src":         "*",
+			"form-action":     "'none'",
+		}}),
+	))
+	assert.Equal(t, "default-src 'self'; script-src 'self' https://unpkg.com 'nonce-abc'; style-src 'self' https://unpkg.com 'nonce-abc'; "+
+		"img-src *; font-src 'self' data:; object-src 'none'; base-uri 'self'; connect-src 'self' https://api.example.com; form-action 'none'", s.policy("abc"))
+
+	assert.Nil(t, newSecurityHeaders(newConfig()))
+}
+
+func TestSecurityHeadersHandler(t *testing.T) {
+	nonceRe := regexp.MustCompile(`script-src 'self' 'nonce-([^']+)'`)
+
+	for _, wrap := range []func(options ...func(*Config)) echo.HandlerFunc{EchoWrapHandler, EchoWrapHandlerV3} {
+		router := echo.New()
+		router.GET("/*", wrap(
+			Provider(StaticDoc([]byte((&mockedSwag{}).ReadDoc()))),
+			SecurityHeaders(&SecurityHeadersConfig{ReferrerPolicy: "same-origin"}),
+		))
+
+		w1 := performRequest(http.MethodGet, "/index.html", router)
+		assert.Equal(t, http.StatusOK, w1.Code)
+		assert.Equal(t, "nosniff", w1.Header().Get("X-Content-Type-Options"))
+		assert.Equal(t, "same-origin", w1.Header().Get("Referrer-Policy"))
+
+		matches := nonceRe.FindStringSubmatch(w1.Header().Get("Content-Security-Policy"))
+		if assert.Len(t, matches, 2) {
+			assert.Contains(t, w1.Body.String(), `<style nonce="`+matches[1]+`">`)
+			assert.Contains(t, w1.Body.String(), `<script nonce="`+matches[1]+`">`)
+		}
+
+		// Every page gets a fresh nonce.
+		w2 := performRequest(http.MethodGet, "/index.html", router)
+		assert.NotEqual(t, w1.Header().Get("Content-Security-Policy"), w2.Header().Get("Content-Security-Policy"))
+
+		w3 := performRequest(http.MethodGet, "/doc.json", router)
+		assert.Equal(t, "nosniff", w3.Header().Get("X-Content-Type-Options"))
+		assert.Empty(t, w3.Header().Get("Content-Security-Policy"))
+
+		w4 := performRequest(http.MethodGet, "/swagger-ui.css", router)
+		assert.Equal(t, "nosniff", w4.Header().Get("X-Content-Type-Options"))
+	}
+
+	router := echo.New()
+	router.GET("/*", EchoWrapHandler())
+
+	w := performRequest(http.MethodGet, "/index.html", router)
+	assert.Empty(t, w.Header().Get("Content-Security-Policy"))
+	assert.Empty(t, w.Header().Get("X-Content-Type-Options"))
+	assert.Contains(t, w.Body.String(), "<style>")
+	assert.NotContains(t, w.Body.String(), "nonce=")
+}
@@ -4,44 +4,248 @@ import (
 	"errors"
 	"html/template"
 	"io"
+	"io/fs"
 	"net/http"
 	"os"
+	pathpkg "path"
 	"path/filepath"
-	"regexp"
+	"strings"
 
 	"github.com/labstack/echo/v5"
 	swaggerFiles "github.com/swaggo/files/v2"
 	"github.com/swaggo/swag"
 	swagV2 "github.com/swaggo/swag/v2"
-	"sigs.k8s.io/yaml"
 )
 
 // Config stores echoSwagger configuration variables.
 type Config struct {
 	// The url pointing to API definition (normally swagger.json or swagger.yaml). Default is `mockedSwag.json`.
-	URLs                 []string
+	URLs []string
+
+	// Named entries for the Swagger UI top-bar spec selector, listed after URLs.
+	SpecURLs []SpecURL
+
 	DocExpansion         string
 	DomID                string
 	InstanceName         string
+	InstanceNames        []string
 	DeepLinking          bool
 	PersistAuthorization bool
 	SyntaxHighlight      bool
 
+	// The default expansion depth of the models section, -1 hides it. Default is 1.
+	DefaultModelsExpandDepth int
+
+	// The default expansion depth of the model on the model-example section. Default is 1.
+	DefaultModelExpandDepth int
+
+	// How the model is shown when an API is first rendered: example or model. Default is
+	// `example`.
+	DefaultModelRendering string
+
+	DisplayOperationID     bool
+	DisplayRequestDuration bool
+
+	// Show the bar filtering the operations by tag.
+	FilterBar bool
+
+	// The initial text of the filter bar, if any. It implies FilterBar.
+	FilterText string
+
+	// The maximum number of tagged operations shown. All are shown if 0.
+	MaxDisplayedTags int
+
+	ShowExtensions       bool
+	ShowCommonExtensions bool
+
+	// Enable "Try it out" for every operation when the page is loaded.
+	TryItOutEnabled bool
+
+	// The HTTP methods "Try it out" is enabled for. All methods if empty.
+	SupportedSubmitMethods []string
+
+	// The order of the operations of each tag: alpha or method. The order of the API
+	// definition if empty.
+	OperationsSorter string
+
+	// The order of the tags: alpha. The order of the API definition if empty.
+	TagsSorter string
+
+	RequestSnippetsEnabled bool
+
+	// Show the request as modified by the request interceptors. Default is true.
+	ShowMutatedRequest bool
+
+	// Send cookies with the requests of "Try it out" (credentials of the fetch API).
+	WithCredentials bool
+
+	// Read the configuration from the query string of the page, e.g. `?docExpansion=full`.
+	QueryConfigEnabled bool
+
+	// The url of the validator badge service, if any.
+	ValidatorURL string
+
 	// The information for OAuth2 integration, if any.
 	OAuth *OAuthConfig
+
+	// The Swagger UI plugins loaded by the page, after DownloadUrl.
+	Plugins []Plugin
+
+	// Add the DownloadUrl plugin, which loads the API definition. Swagger UI loads none
+	// without it, so it can only be dropped for one of Plugins replacing it. Default is true.
+	DownloadURLPlugin bool
+
+	// The layout of the Swagger UI page: StandaloneLayout, with the topbar selecting the spec,
+	// or BaseLayout, showing the primary spec. Default is `StandaloneLayout`.
+	Layout string
+
+	// Hide the topbar of StandaloneLayout.
+	HideTopbar bool
+
+	// Preauthorize returns the values of the security schemes, by name, authorized when
+	// the Swagger UI page is loaded, e.g. the bearer token of the session. The value of a
+	// basic scheme is `username:password`.
+	Preauthorize func(*echo.Context) map[string]string
+
+	// The request and response interceptors of Swagger UI, if any.
+	Interceptors *InterceptorConfig
+
+	// The proxy forwarding the "Try it out" requests to the API hosts, if any. It is
+	// served under proxy/ next to index.html, for every method.
+	TryItOutProxy *ProxyConfig
+
+	// The url of the OAuth2 redirect page, oauth2-redirect.html, registered with the IAM
+	// provider. Default is the page served by the handler, at the url the request was made
+	// for, honoring X-Forwarded-Proto, X-Forwarded-Host and X-Forwarded-Prefix.
+	OAuth2RedirectURL string
+
+	// The source of the API definition. Defaults to the swag registry matching the handler.
+	DocProvider DocProvider
+
+	// Rewrite the host, basePath and schemes (Swagger 2.0) or servers (OpenAPI 3) of the
+	// API definition to match the incoming request.
+	DynamicHost bool
+
+	// Cache the rendered doc.json and doc.yaml per instance. By default only the documents
+	// of SwagRegistry, SwagV2Registry, StaticDoc and FSDoc are cached, since other providers
+	// may return a different document per request.
+	DocCache *bool
+
+	// The Cache-Control header sent with doc.json and doc.yaml. Default is `no-cache`.
+	DocCacheControl string
+
+	// The Cache-Control header sent with the Swagger UI assets, if any.
+	AssetsCacheControl string
+
+	// The title of the Swagger UI page. Default is `Swagger UI`.
+	Title string
+
+	// The urls of additional stylesheets linked by the Swagger UI page.
+	Stylesheets []string
+
+	// CSS rules added to the Swagger UI page.
+	CustomCSS template.CSS
+
+	// The url of the image replacing the Swagger logo in the topbar, if any.
+	LogoURL string
+
+	// The file system serving the Swagger UI assets. Default is the Swagger UI distribution
+	// bundled by github.com/swaggo/files/v2.
+	AssetsFS fs.FS
+
+	// The base url the Swagger UI page loads swagger-ui.css, swagger-ui-bundle.js and
+	// swagger-ui-standalone-preset.js from, e.g. a CDN. The assets are loaded from the
+	// handler if empty.
+	AssetsBaseURL string
+
+	// The Subresource Integrity hashes of the assets loaded from AssetsBaseURL, by file name.
+	AssetsIntegrity map[string]string
+
+	// The file system serving favicon-32x32.png and favicon-16x16.png in place of the
+	// Swagger favicons, if any.
+	FaviconFS fs.FS
+
+	// The page served as index.html in place of Swagger UI, if any.
+	Renderer PageRenderer
+
+	// The template rendering index.html, executed with IndexData. Default is the Swagger UI page.
+	IndexTemplate *template.Template
+
+	indexTemplateFS       fs.FS
+	indexTemplatePatterns []string
+
+	// Serve the Swagger 2.0 document converted to OpenAPI 3.0 as openapi.json and openapi.yaml.
+	ConvertToOpenAPI3 bool
+
+	// Serve gzip and brotli compressed Swagger UI assets to clients accepting them.
+	CompressAssets bool
+
+	// The credentials required to access the documentation, if any.
+	BasicAuth *BasicAuthConfig
+
+	// Authorizer denies access to the documentation when it returns false.
+	Authorizer func(*echo.Context) bool
+
+	// The IP addresses and CIDR ranges allowed to access the documentation. Any address is
+	// allowed if empty.
+	AllowedIPs []string
+
+	// The security headers sent with the documentation, if any: a Content-Security-Policy
+	// with a per-request nonce for the Swagger UI page, X-Content-Type-Options and
+	// Referrer-Policy.
+	SecurityHeaders *SecurityHeadersConfig
+
+	// Filter prunes the operations it rejects from the served API definition.
+	Filter OperationFilter
+
+	// RequestFilter selects an additional OperationFilter per request. A nil filter keeps
+	// every operation.
+	RequestFilter func(*echo.Context) OperationFilter
+}
+
+// SpecURL is a named entry of the Swagger UI top-bar spec selector.
+type SpecURL struct {
+	// The name displayed in the selector.
+	Name string
+
+	// The url pointing to API definition.
+	URL string
+
+	// Primary selects this entry when the page is loaded. If several entries are
+	// primary, the first one wins.
+	Primary bool
 }
 
 // OAuthConfig stores configuration for Swagger UI OAuth2 integration. See
 // https://swagger.io/docs/open-source-tools/swagger-ui/usage/oauth2/ for further details.
 type OAuthConfig struct {
 	// The ID of the client sent to the OAuth2 IAM provider.
-	ClientId string
+	ClientId string `json:"clientId"`
 
 	// The OAuth2 realm that the client should operate in. If not applicable, use empty string.
-	Realm string
+	Realm string `json:"realm"`
 
 	// The name to display for the application in the authentication popup.
-	AppName string
+	AppName string `json:"appName"`
+
+	// The secret of the client. Never use it in production, the page exposes it to every
+	// visitor.
+	ClientSecret string `json:"clientSecret,omitempty"`
+
+	// The scopes selected by default in the authentication popup.
+	Scopes []string `json:"scopes,omitempty"`
+
+	// The separator of the scopes sent to the IAM provider. Default is a space.
+	ScopeSeparator string `json:"scopeSeparator,omitempty"`
+
+	// Additional query parameters added to the authorization and token requests.
+	AdditionalQueryStringParams map[string]string `json:"additionalQueryStringParams,omitempty"`
+
+	// Send the client credentials with HTTP Basic authentication in the accessCode flow.
+	UseBasicAuthenticationWithAccessCodeGrant bool `json:"useBasicAuthenticationWithAccessCodeGrant,omitempty"`
+
+	// Use Proof Key for Code Exchange (PKCE) in the authorizationCode flow.
+	UsePkceWithAuthorizationCodeGrant bool `json:"usePkceWithAuthorizationCodeGrant,omitempty"`
 }
 
 // URL presents the url pointing to API definition (normally swagger.json or swagger.yaml).
@@ -51,6 +255,13 @@ func URL(url string) func(*Config) {
 	}
 }
 
+// SpecURLs adds named entries to the Swagger UI top-bar spec selector.
+func SpecURLs(specs ...SpecURL) func(*Config) {
+	return func(c *Config) {
+		c.SpecURLs = append(c.SpecURLs, specs...)
+	}
+}
+
 // DeepLinking true, false.
 func DeepLinking(deepLinking bool) func(*Config) {
 	return func(c *Config) {
@@ -86,6 +297,14 @@ func InstanceName(instanceName string) func(*Config) {
 	}
 }
 
+// InstanceNames specified additional swag instance names. Each instance is served under
+// its own sub-path (e.g. `<instance>/doc.json`) and listed in the spec selector.
+func InstanceNames(instanceNames ...string) func(*Config) {
+	return func(c *Config) {
+		c.InstanceNames = append(c.InstanceNames, instanceNames...)
+	}
+}
+
 // PersistAuthorization Persist authorization information over browser close/refresh.
 // Defaults to false.
 func PersistAuthorization(persistAuthorization bool) func(*Config) {
@@ -94,21 +313,545 @@ func PersistAuthorization(persistAuthorization bool) func(*Config) {
 	}
 }
 
+// DefaultModelsExpandDepth the expansion depth of the models section, -1 hides it.
+// Defaults to 1.
+func DefaultModelsExpandDepth(depth int) func(*Config) {
+	return func(c *Config) {
+		c.DefaultModelsExpandDepth = depth
+	}
+}
+
+// DefaultModelExpandDepth the expansion depth of the model on the model-example section.
+// Defaults to 1.
+func DefaultModelExpandDepth(depth int) func(*Config) {
+	return func(c *Config) {
+		c.DefaultModelExpandDepth = depth
+	}
+}
+
+// DefaultModelRendering example, model. Defaults to example.
+func DefaultModelRendering(rendering string) func(*Config) {
+	return func(c *Config) {
+		c.DefaultModelRendering = rendering
+	}
+}
+
+// DisplayOperationID true, false.
+func DisplayOperationID(displayOperationID bool) func(*Config) {
+	return func(c *Config) {
+		c.DisplayOperationID = displayOperationID
+	}
+}
+
+// DisplayRequestDuration true, false.
+func DisplayRequestDuration(displayRequestDuration bool) func(*Config) {
+	return func(c *Config) {
+		c.DisplayRequestDuration = displayRequestDuration
+	}
+}
+
+// FilterBar shows the bar filtering the operations by tag.
+func FilterBar(filterBar bool) func(*Config) {
+	return func(c *Config) {
+		c.FilterBar = filterBar
+	}
+}
+
+// FilterText shows the bar filtering the operations by tag, filled with text.
+func FilterText(text string) func(*Config) {
+	return func(c *Config) {
+		c.FilterText = text
+	}
+}
+
+// MaxDisplayedTags limits the number of tagged operations shown. Defaults to 0, all.
+func MaxDisplayedTags(maxDisplayedTags int) func(*Config) {
+	return func(c *Config) {
+		c.MaxDisplayedTags = maxDisplayedTags
+	}
+}
+
+// ShowExtensions true, false.
+func ShowExtensions(showExtensions bool) func(*Config) {
+	return func(c *Config) {
+		c.ShowExtensions = showExtensions
+	}
+}
+
+// ShowCommonExtensions true, false.
+func ShowCommonExtensions(showCommonExtensions bool) func(*Config) {
+	return func(c *Config) {
+		c.ShowCommonExtensions = showCommonExtensions
+	}
+}
+
+// TryItOutEnabled true, false.
+func TryItOutEnabled(tryItOutEnabled bool) func(*Config) {
+	return func(c *Config) {
+		c.TryItOutEnabled = tryItOutEnabled
+	}
+}
+
+// SupportedSubmitMethods get, put, post, delete, options, head, patch, trace.
+func SupportedSubmitMethods(methods ...string) func(*Config) {
+	return func(c *Config) {
+		c.SupportedSubmitMethods = methods
+	}
+}
+
+// OperationsSorter alpha, method.
+func OperationsSorter(sorter string) func(*Config) {
+	return func(c *Config) {
+		c.OperationsSorter = sorter
+	}
+}
+
+// TagsSorter alpha.
+func TagsSorter(sorter string) func(*Config) {
+	return func(c *Config) {
+		c.TagsSorter = sorter
+	}
+}
+
+// RequestSnippetsEnabled true, false.
+func RequestSnippetsEnabled(requestSnippetsEnabled bool) func(*Config) {
+	return func(c *Config) {
+		c.RequestSnippetsEnabled = requestSnippetsEnabled
+	}
+}
+
+// ShowMutatedRequest true, false. Defaults to true.
+func ShowMutatedRequest(showMutatedRequest bool) func(*Config) {
+	return func(c *Config) {
+		c.ShowMutatedRequest = showMutatedRequest
+	}
+}
+
+// WithCredentials true, false.
+func WithCredentials(withCredentials bool) func(*Config) {
+	return func(c *Config) {
+		c.WithCredentials = withCredentials
+	}
+}
+
+// QueryConfigEnabled true, false.
+func QueryConfigEnabled(queryConfigEnabled bool) func(*Config) {
+	return func(c *Config) {
+		c.QueryConfigEnabled = queryConfigEnabled
+	}
+}
+
+// ValidatorURL the url of the validator badge service. Defaults to none.
+func ValidatorURL(url string) func(*Config) {
+	return func(c *Config) {
+		c.ValidatorURL = url
+	}
+}
+
+// DownloadURLPlugin true, false. Defaults to true; false requires a plugin loading the API
+// definition in its place.
+func DownloadURLPlugin(downloadURLPlugin bool) func(*Config) {
+	return func(c *Config) {
+		c.DownloadURLPlugin = downloadURLPlugin
+	}
+}
+
+// Layout StandaloneLayout, BaseLayout. Defaults to StandaloneLayout.
+func Layout(layout string) func(*Config) {
+	return func(c *Config) {
+		c.Layout = layout
+	}
+}
+
+// HideTopbar true, false.
+func HideTopbar(hideTopbar bool) func(*Config) {
+	return func(c *Config) {
+		c.HideTopbar = hideTopbar
+	}
+}
+
+// Preauthorize authorizes the security schemes, by name, with the values returned by
+// preauthorize when the Swagger UI page is loaded. The page is then sent with
+// `Cache-Control: no-store`.
+func Preauthorize(preauthorize func(*echo.Context) map[string]string) func(*Config) {
+	return func(c *Config) {
+		c.Preauthorize = preauthorize
+	}
+}
+
+// Interceptors sets the request and response interceptors of Swagger UI, served as
+// interceptors.js.
+func Interceptors(config *InterceptorConfig) func(*Config) {
+	return func(c *Config) {
+		c.Interceptors = config
+	}
+}
+
+// TryItOutProxy forwards the "Try it out" requests to the API hosts through the handler, so
+// that they are not subject to CORS. The handler must be registered for every method,
+// e.g. with echo.Echo.Any.
+func TryItOutProxy(config *ProxyConfig) func(*Config) {
+	return func(c *Config) {
+		c.TryItOutProxy = config
+	}
+}
+
+// OAuth2RedirectURL the url of oauth2-redirect.html registered with the IAM provider.
+func OAuth2RedirectURL(url string) func(*Config) {
+	return func(c *Config) {
+		c.OAuth2RedirectURL = url
+	}
+}
+
 func OAuth(config *OAuthConfig) func(*Config) {
 	return func(c *Config) {
 		c.OAuth = config
 	}
 }
 
+// IndexData is passed to the index template. Besides the fields of Config, it holds the
+// values derived from the handler configuration and from the request.
+type IndexData struct {
+	*Config
+
+	// The entries of the spec selector: URLs, SpecURLs and InstanceNames.
+	Specs []SpecURL
+
+	// The name of the spec selected when the page is loaded, if any.
+	PrimaryName string
+
+	// The path the handler is mounted on, e.g. `/swagger/`.
+	BasePath string
+
+	// The nonce of the Content-Security-Policy of the response, if any.
+	Nonce string
+
+	// The configuration of SwaggerUIBundle as a JavaScript object literal.
+	UIConfig template.JS
+
+	// The configuration of ui.initOAuth as a JavaScript object literal, if OAuth is set.
+	InitOAuth template.JS
+
+	// The values returned by Config.Preauthorize for the request as a JavaScript object
+	// literal, if any.
+	Preauthorization template.JS
+
+	ui *uiConfig
+}
+
+// AssetURL returns the url the Swagger UI page loads the named asset from.
+func (d *IndexData) AssetURL(name string) string {
+	if d.AssetsBaseURL == "" {
+		return "./" + name
+	}
+
+	return strings.TrimSuffix(d.AssetsBaseURL, "/") + "/" + name
+}
+
+// Integrity returns the Subresource Integrity hash of the named asset, if any.
+func (d *IndexData) Integrity(name string) string {
+	if d.AssetsBaseURL == "" {
+		return ""
+	}
+
+	return d.AssetsIntegrity[name]
+}
+
+func newIndexData(config *Config) (*IndexData, error) {
+	data := &IndexData{Config: config}
+	for _, url := range config.URLs {
+		data.Specs = append(data.Specs, SpecURL{Name: url, URL: url})
+	}
+	data.Specs = append(data.Specs, config.SpecURLs...)
+	for _, name := range config.InstanceNames {
+		data.Specs = append(data.Specs, SpecURL{Name: name, URL: name + "/doc.json"})
+	}
+
+	for _, spec := range data.Specs {
+		if spec.Primary {
+			data.PrimaryName = spec.Name
+			break
+		}
+	}
+
+	data.ui = newUIConfig(data)
+
+	var err error
+	if data.UIConfig, data.InitOAuth, err = uiScripts(data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// swaggerUIFS returns the file system serving the Swagger UI assets.
+func (config *Config) swaggerUIFS() fs.FS {
+	if config.AssetsFS != nil {
+		return config.AssetsFS
+	}
+
+	return swaggerFiles.FS
+}
+
+// assetFS returns the file system serving the named Swagger UI asset.
+func (config *Config) assetFS(name string) fs.FS {
+	switch name {
+	case "favicon-32x32.png", "favicon-16x16.png":
+		if config.FaviconFS != nil {
+			return config.FaviconFS
+		}
+	}
+
+	return config.swaggerUIFS()
+}
+
+// isDocFile reports whether name is one of the documents rendered from the API definition.
+func (config *Config) isDocFile(name string) bool {
+	switch name {
+	case "doc.json", "doc.yaml":
+		return true
+	case "openapi.json", "openapi.yaml":
+		return config.ConvertToOpenAPI3
+	}
+
+	return false
+}
+
+// docInstance resolves the swag instance a document request is made for from
+// the wildcard path below the handler mount point.
+func (config *Config) docInstance(wildcard string) (string, bool) {
+	dir := pathpkg.Dir(wildcard)
+	if dir == "." || dir == "/" {
+		return config.InstanceName, true
+	}
+
+	dir = strings.TrimPrefix(dir, "/")
+	for _, instanceName := range config.InstanceNames {
+		if instanceName == dir {
+			return instanceName, true
+		}
+	}
+
+	return "", false
+}
+
+// Provider sets the source of the API definition served as doc.json and doc.yaml.
+func Provider(provider DocProvider) func(*Config) {
+	return func(c *Config) {
+		c.DocProvider = provider
+	}
+}
+
+// DynamicHost rewrites the host, basePath and schemes (Swagger 2.0) or servers (OpenAPI 3)
+// of the served API definition from the Host, X-Forwarded-Host, X-Forwarded-Proto and
+// X-Forwarded-Prefix request headers. Defaults to false.
+func DynamicHost(dynamicHost bool) func(*Config) {
+	return func(c *Config) {
+		c.DynamicHost = dynamicHost
+	}
+}
+
+// DocCache caches the rendered doc.json and doc.yaml per instance. Defaults to true for
+// SwagRegistry, SwagV2Registry, StaticDoc and FSDoc, and to false for other providers.
+func DocCache(docCache bool) func(*Config) {
+	return func(c *Config) {
+		c.DocCache = &docCache
+	}
+}
+
+// DocCacheControl sets the Cache-Control header of doc.json and doc.yaml.
+func DocCacheControl(cacheControl string) func(*Config) {
+	return func(c *Config) {
+		c.DocCacheControl = cacheControl
+	}
+}
+
+// AssetsCacheControl sets the Cache-Control header of the Swagger UI assets.
+func AssetsCacheControl(cacheControl string) func(*Config) {
+	return func(c *Config) {
+		c.AssetsCacheControl = cacheControl
+	}
+}
+
+// Title sets the title of the Swagger UI page.
+func Title(title string) func(*Config) {
+	return func(c *Config) {
+		c.Title = title
+	}
+}
+
+// Stylesheets links additional stylesheets from the Swagger UI page.
+func Stylesheets(urls ...string) func(*Config) {
+	return func(c *Config) {
+		c.Stylesheets = append(c.Stylesheets, urls...)
+	}
+}
+
+// CustomCSS adds CSS rules to the Swagger UI page. The rules are trusted and rendered
+// unescaped.
+func CustomCSS(css string) func(*Config) {
+	return func(c *Config) {
+		c.CustomCSS += template.CSS(css)
+	}
+}
+
+// LogoURL replaces the Swagger logo in the topbar with the image at url.
+func LogoURL(url string) func(*Config) {
+	return func(c *Config) {
+		c.LogoURL = url
+	}
+}
+
+// AssetsFS serves the Swagger UI assets from fsys, e.g. an embedded swagger-ui dist directory
+// (use fs.Sub to strip its prefix).
+func AssetsFS(fsys fs.FS) func(*Config) {
+	return func(c *Config) {
+		c.AssetsFS = fsys
+	}
+}
+
+// AssetsCDN loads swagger-ui.css, swagger-ui-bundle.js and swagger-ui-standalone-preset.js
+// from baseURL, e.g. `https://unpkg.com/swagger-ui-dist@5.17.14`. integrity holds the
+// Subresource Integrity hash of each file, by file name, and may be nil.
+func AssetsCDN(baseURL string, integrity map[string]string) func(*Config) {
+	return func(c *Config) {
+		c.AssetsBaseURL = baseURL
+		c.AssetsIntegrity = integrity
+	}
+}
+
+// FaviconFS serves favicon-32x32.png and favicon-16x16.png from fsys in place of the
+// Swagger favicons.
+func FaviconFS(fsys fs.FS) func(*Config) {
+	return func(c *Config) {
+		c.FaviconFS = fsys
+	}
+}
+
+// IndexTemplate renders index.html with tmpl, executed with IndexData.
+func IndexTemplate(tmpl *template.Template) func(*Config) {
+	return func(c *Config) {
+		c.IndexTemplate = tmpl
+	}
+}
+
+// IndexTemplateFS renders index.html with the templates matching patterns in fsys, see
+// template.ParseFS. The first matching template is executed with IndexData.
+func IndexTemplateFS(fsys fs.FS, patterns ...string) func(*Config) {
+	return func(c *Config) {
+		c.indexTemplateFS = fsys
+		c.indexTemplatePatterns = patterns
+	}
+}
+
+// Renderer serves the page rendered by renderer as index.html in place of Swagger UI, e.g.
+// Redoc() or Scalar(). The API definition is served as usual.
+func Renderer(renderer PageRenderer) func(*Config) {
+	return func(c *Config) {
+		c.Renderer = renderer
+	}
+}
+
+// ConvertToOpenAPI3 serves the Swagger 2.0 document of each instance converted to OpenAPI 3.0
+// as openapi.json and openapi.yaml, next to doc.json and doc.yaml. Documents already in
+// OpenAPI 3 are served unchanged. Defaults to false.
+func ConvertToOpenAPI3(convertToOpenAPI3 bool) func(*Config) {
+	return func(c *Config) {
+		c.ConvertToOpenAPI3 = convertToOpenAPI3
+	}
+}
+
+// CompressAssets serves gzip and brotli compressed Swagger UI assets to clients accepting
+// them. Assets are compressed on first use and kept in memory. Defaults to false.
+func CompressAssets(compressAssets bool) func(*Config) {
+	return func(c *Config) {
+		c.CompressAssets = compressAssets
+	}
+}
+
+// BasicAuth requires HTTP Basic authentication with the given credentials. Requests with
+// missing or wrong credentials are answered with 401 Unauthorized.
+func BasicAuth(username, password string) func(*Config) {
+	return func(c *Config) {
+		c.BasicAuth = &BasicAuthConfig{Username: username, Password: password}
+	}
+}
+
+// SecurityHeaders sends a Content-Security-Policy with a per-request nonce for the Swagger UI
+// page, or the page of the Renderer, X-Content-Type-Options and Referrer-Policy. Templates
+// set by IndexTemplate must add the nonce attribute, {{.Nonce}}, to their inline scripts and
+// styles, and renderers the nonce passed to PageRenderer.Render.
+func SecurityHeaders(config *SecurityHeadersConfig) func(*Config) {
+	return func(c *Config) {
+		c.SecurityHeaders = config
+	}
+}
+
+// Authorizer answers requests with 403 Forbidden when authorizer returns false.
+func Authorizer(authorizer func(*echo.Context) bool) func(*Config) {
+	return func(c *Config) {
+		c.Authorizer = authorizer
+	}
+}
+
+// AllowIPs restricts access to the given IP addresses and CIDR ranges. Other clients are
+// answered with 403 Forbidden. The client address is the remote address of the request,
+// or the one reported by echo.Context.RealIP if Echo.IPExtractor is set, e.g. to
+// echo.ExtractIPFromXFFHeader behind a trusted proxy. Invalid entries match no client and
+// are reported by New and NewV3.
+func AllowIPs(allowedIPs ...string) func(*Config) {
+	return func(c *Config) {
+		c.AllowedIPs = append(c.AllowedIPs, allowedIPs...)
+	}
+}
+
+// Filter serves only the operations kept by filter, along with the definitions they
+// reference.
+func Filter(filter OperationFilter) func(*Config) {
+	return func(c *Config) {
+		c.Filter = filter
+	}
+}
+
+// RequestFilter serves only the operations kept by the OperationFilter selected for each
+// request, e.g. IncludeTags("public") for anonymous users.
+func RequestFilter(requestFilter func(*echo.Context) OperationFilter) func(*Config) {
+	return func(c *Config) {
+		c.RequestFilter = requestFilter
+	}
+}
+
+// parseIndexTemplate returns the template rendering index.html.
+func (config *Config) parseIndexTemplate() (*template.Template, error) {
+	if config.indexTemplateFS != nil {
+		return template.ParseFS(config.indexTemplateFS, config.indexTemplatePatterns...)
+	}
+
+	if config.IndexTemplate != nil {
+		return config.IndexTemplate, nil
+	}
+
+	// create a template with name
+	return template.New("swagger_index.html").Parse(indexTemplate)
+}
+
 func newConfig(configFns ...func(*Config)) *Config {
 	config := Config{
-		URLs:                 []string{"doc.json", "doc.yaml"},
-		DocExpansion:         "list",
-		DomID:                "swagger-ui",
-		InstanceName:         "swagger",
-		DeepLinking:          true,
-		PersistAuthorization: false,
-		SyntaxHighlight:      true,
+		URLs:                     []string{"doc.json", "doc.yaml"},
+		DocExpansion:             "list",
+		DomID:                    "swagger-ui",
+		InstanceName:             "swagger",
+		DeepLinking:              true,
+		PersistAuthorization:     false,
+		SyntaxHighlight:          true,
+		DefaultModelsExpandDepth: 1,
+		DefaultModelExpandDepth:  1,
+		DefaultModelRendering:    "example",
+		ShowMutatedRequest:       true,
+		DownloadURLPlugin:        true,
+		Layout:                   "StandaloneLayout",
+		Title:                    "Swagger UI",
+		DocCacheControl:          "no-cache",
 	}
 
 	for _, fn := range configFns {
@@ -128,68 +871,51 @@ var (
 	WrapHandlerV3 = EchoWrapHandlerV3()
 )
 
-// EchoWrapHandler wraps `http.Handler` into `echo.HandlerFunc`.
+// EchoWrapHandler wraps `http.Handler` into `echo.HandlerFunc`. It panics if the index
+// template cannot be parsed or the configuration is invalid, see New.
 func EchoWrapHandler(options ...func(*Config)) echo.HandlerFunc {
-	config := newConfig(options...)
-
-	// create a template with name
-	index, _ := template.New("swagger_index.html").Parse(indexTemplate)
+	handler, err := newWrapHandler(newConfig(options...))
+	if err != nil {
+		panic(err)
+	}
 
-	var re = regexp.MustCompile(`^(.*/)([^?].*)?[?|.]*$`)
+	return handler
+}
 
-	return func(c *echo.Context) error {
-		if c.Request().Method != http.MethodGet {
-			return c.String(http.StatusMethodNotAllowed, http.StatusText(http.StatusMethodNotAllowed))
+// New returns the handler of EchoWrapHandler, or an error if the index template cannot be
+// parsed, the configuration is invalid or incomplete, e.g. OAuth without a ClientId, or,
+// unless a DocProvider is set, an instance is not registered with github.com/swaggo/swag.
+func New(options ...func(*Config)) (echo.HandlerFunc, error) {
+	config := newConfig(options...)
+	if err := config.checkFields(); err != nil {
+		return nil, err
+	}
+	if config.DocProvider == nil {
+		if err := config.checkInstances(func(name string) bool { return swag.GetSwagger(name) != nil }); err != nil {
+			return nil, err
 		}
+	}
 
-		matches := re.FindStringSubmatch(c.Request().RequestURI)
-		path := matches[2]
+	return newWrapHandler(config)
+}
 
-		switch filepath.Ext(path) {
-		case ".html":
-			c.Response().Header().Set("Content-Type", "text/html; charset=utf-8")
-		case ".css":
-			c.Response().Header().Set("Content-Type", "text/css; charset=utf-8")
-		case ".js":
-			c.Response().Header().Set("Content-Type", "application/javascript")
-		case ".json":
-			c.Response().Header().Set("Content-Type", "application/json; charset=utf-8")
-		case ".yaml":
-			c.Response().Header().Set("Content-Type", "text/plain; charset=utf-8")
-		case ".png":
-			c.Response().Header().Set("Content-Type", "image/png")
-		}
+func newWrapHandler(config *Config) (echo.HandlerFunc, error) {
+	h, err := newSwaggerHandler(config, SwagRegistry, false)
+	if err != nil {
+		return nil, err
+	}
 
-		switch path {
-		case "":
-			return c.Redirect(http.StatusMovedPermanently, matches[1]+"/"+"index.html")
-		case "index.html":
-			pr, pw := io.Pipe()
-			go func() {
-				defer pw.Close()
-				_ = index.Execute(pw, config)
-			}()
-			return c.Stream(http.StatusOK, "text/html; charset=utf-8", pr)
-		case "doc.json":
-			doc, err := swag.ReadDoc(config.InstanceName)
-			if err != nil {
-				return c.String(http.StatusInternalServerError, err.Error())
-			}
-			return c.String(http.StatusOK, doc)
-		case "doc.yaml":
-			jsonString, err := swag.ReadDoc(config.InstanceName)
-			if err != nil {
-				return c.String(http.StatusInternalServerError, err.Error())
-			}
-			doc, err := yaml.JSONToYAML([]byte(jsonString))
-			if err != nil {
-				return c.String(http.StatusInternalServerError, err.Error())
-			}
-			return c.String(http.StatusOK, string(doc))
-		}
-		c.Request().URL.Path = matches[2]
+	writeIndex := func(c *echo.Context, data *IndexData) error {
+		pr, pw := io.Pipe()
+		go func() {
+			defer pw.Close()
+			_ = h.index.Execute(pw, data)
+		}()
+		return c.Stream(http.StatusOK, "text/html; charset=utf-8", pr)
+	}
 
-		f, err := swaggerFiles.FS.Open(matches[2])
+	writeAsset := func(c *echo.Context, name string) error {
+		f, err := config.assetFS(name).Open(name)
 		if errors.Is(err, os.ErrNotExist) {
 			// If the file is not found, return 404
 			return c.String(http.StatusNotFound, http.StatusText(http.StatusNotFound))
@@ -198,26 +924,171 @@ func EchoWrapHandler(options ...func(*Config)) echo.HandlerFunc {
 		}
 		defer f.Close()
 
+		if served, err := h.serveCompressed(c, name); served {
+			return err
+		}
+
 		return c.Stream(http.StatusOK, c.Response().Header().Get("Content-Type"), f)
 	}
+
+	return h.handlerFunc(writeIndex, writeAsset), nil
 }
 
-// EchoWrapHandler wraps `http.Handler` into `echo.HandlerFunc`.
+// EchoWrapHandlerV3 wraps `http.Handler` into `echo.HandlerFunc` for documents registered with
+// swag v2. OpenAPI 3.1 documents are served with the application/openapi+json and
+// application/openapi+yaml media types. It panics if the index template cannot be parsed
+// or the configuration is invalid, see NewV3.
 func EchoWrapHandlerV3(options ...func(*Config)) echo.HandlerFunc {
+	handler, err := newWrapHandlerV3(newConfig(options...))
+	if err != nil {
+		panic(err)
+	}
+
+	return handler
+}
+
+// NewV3 returns the handler of EchoWrapHandlerV3, or an error if the index template cannot
+// be parsed, the configuration is invalid or incomplete or, unless a DocProvider is set, an
+// instance is not registered with github.com/swaggo/swag/v2.
+func NewV3(options ...func(*Config)) (echo.HandlerFunc, error) {
 	config := newConfig(options...)
+	if err := config.checkFields(); err != nil {
+		return nil, err
+	}
+	if config.DocProvider == nil {
+		if err := config.checkInstances(func(name string) bool { return swagV2.GetSwagger(name) != nil }); err != nil {
+			return nil, err
+		}
+	}
 
-	// create a template with name
-	index, _ := template.New("swagger_index.html").Parse(indexTemplate)
+	return newWrapHandlerV3(config)
+}
+
+func newWrapHandlerV3(config *Config) (echo.HandlerFunc, error) {
+	h, err := newSwaggerHandler(config, SwagV2Registry, true)
+	if err != nil {
+		return nil, err
+	}
+
+	writeIndex := func(c *echo.Context, data *IndexData) error {
+		defer flush(c)
+
+		_ = h.index.Execute(c.Response(), data)
+
+		return nil
+	}
+
+	writeAsset := func(c *echo.Context, name string) error {
+		defer flush(c)
+
+		if served, err := h.serveCompressed(c, name); served {
+			return err
+		}
+
+		http.FileServer(http.FS(config.assetFS(name))).ServeHTTP(c.Response(), c.Request())
+
+		return nil
+	}
+
+	return h.handlerFunc(writeIndex, writeAsset), nil
+}
+
+// flush flushes the response once written.
+func flush(c *echo.Context) {
+	// This check fixes an error introduced here: https://github.com/labstack/echo/blob/8da8e161380fd926d4341721f0328f1e94d6d0a2/response.go#L86-L88
+	if flusher, ok := c.Response().(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// swaggerHandler serves the documentation of EchoWrapHandler and EchoWrapHandlerV3, which
+// only differ in the way they write the Swagger UI page and assets.
+type swaggerHandler struct {
+	config       *Config
+	docs         *docCache
+	assets       *compressedAssets
+	access       *accessControl
+	security     *securityHeaders
+	index        *template.Template
+	base         *IndexData
+	interceptors bool
+	proxy        *tryItOutProxy
+
+	// The hosts of the API definitions, as provided rather than rewritten by DynamicHost.
+	specHosts func(c *echo.Context) []string
+}
+
+func newSwaggerHandler(config *Config, fallback DocProvider, openAPIMediaTypes bool) (*swaggerHandler, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+
+	h := &swaggerHandler{
+		config:   config,
+		docs:     newDocCache(config, fallback, openAPIMediaTypes),
+		assets:   newCompressedAssets(config.swaggerUIFS()),
+		access:   newAccessControl(config),
+		security: newSecurityHeaders(config),
+	}
+
+	var err error
+	if h.index, err = config.parseIndexTemplate(); err != nil {
+		return nil, err
+	}
+	if h.base, err = newIndexData(config); err != nil {
+		return nil, err
+	}
+	// The hosts are read from the provider as is, since DynamicHost rewrites them from the
+	// request, which would let any client choose the hosts requests are forwarded to.
+	provider := config.DocProvider
+	if provider == nil {
+		provider = fallback
+	}
+	h.specHosts = func(c *echo.Context) []string {
+		return providerHosts(c, provider, config.instances())
+	}
+	h.interceptors = config.Interceptors != nil || config.TryItOutProxy != nil
+	h.proxy = newTryItOutProxy(config, h.specHosts)
+
+	return h, nil
+}
 
-	var re = regexp.MustCompile(`^(.*/)([^?].*)?[?|.]*$`)
+// handlerFunc returns the handler serving every endpoint, writing the Swagger UI page with
+// writeIndex and the Swagger UI assets with writeAsset.
+func (h *swaggerHandler) handlerFunc(writeIndex func(c *echo.Context, data *IndexData) error, writeAsset func(c *echo.Context, name string) error) echo.HandlerFunc {
+	config := h.config
 
 	return func(c *echo.Context) error {
+		h.security.set(c)
+
+		if status := h.access.check(c); status != 0 {
+			return c.String(status, http.StatusText(status))
+		}
+
+		if target, ok := strings.CutPrefix(c.Param("*"), proxyDir); ok && h.proxy != nil {
+			return h.proxy.serve(c, target)
+		}
+
 		if c.Request().Method != http.MethodGet {
-			return echo.NewHTTPError(http.StatusMethodNotAllowed, http.StatusText(http.StatusMethodNotAllowed))
+			return c.String(http.StatusMethodNotAllowed, http.StatusText(http.StatusMethodNotAllowed))
 		}
 
-		matches := re.FindStringSubmatch(c.Request().RequestURI)
-		path := matches[2]
+		switch pathpkg.Base(c.Request().URL.Path) {
+		case oauth2RedirectPage:
+			return config.serveOAuth2Redirect(c)
+		case interceptorsScript:
+			if h.interceptors {
+				return h.serveInterceptors(c)
+			}
+		}
+
+		if served, err := config.servePlugin(c, c.Param("*")); served {
+			return err
+		}
+
+		// The request path is split into the path the handler is mounted on and the
+		// requested file, leaving out the query string.
+		basePath, path := pathpkg.Split(c.Request().URL.Path)
 
 		switch filepath.Ext(path) {
 		case ".html":
@@ -234,53 +1105,74 @@ func EchoWrapHandlerV3(options ...func(*Config)) echo.HandlerFunc {
 			c.Response().Header().Set("Content-Type", "image/png")
 		}
 
-		response := c.Response()
-		// This check fixes an error introduced here: https://github.com/labstack/echo/blob/8da8e161380fd926d4341721f0328f1e94d6d0a2/response.go#L86-L88
-		if flusher, ok := response.(http.Flusher); ok {
-			defer flusher.Flush()
+		if config.isDocFile(path) {
+			instanceName, ok := config.docInstance(c.Param("*"))
+			if !ok {
+				return c.String(http.StatusNotFound, http.StatusText(http.StatusNotFound))
+			}
+
+			return h.docs.serve(c, instanceName, path)
 		}
 
 		switch path {
 		case "":
-			_ = c.Redirect(http.StatusMovedPermanently, matches[1]+"/"+"index.html")
+			return c.Redirect(http.StatusMovedPermanently, basePath+"/"+"index.html")
 		case "index.html":
-			_ = index.Execute(c.Response(), config)
-		case "doc.json":
-			doc, err := swagV2.ReadDoc(config.InstanceName)
+			nonce, err := h.security.setPolicy(c)
 			if err != nil {
-				return c.String(http.StatusInternalServerError, err.Error())
+				return err
 			}
 
-			_, _ = c.Response().Write([]byte(doc))
-		case "doc.yaml":
-			jsonString, err := swagV2.ReadDoc(config.InstanceName)
-			if err != nil {
-				return c.String(http.StatusInternalServerError, err.Error())
+			if config.Renderer != nil {
+				return config.Renderer.Render(c, h.base.Specs, nonce)
 			}
-			doc, err := yaml.JSONToYAML([]byte(jsonString))
-			if err != nil {
-				return c.String(http.StatusInternalServerError, err.Error())
+
+			data := *h.base
+			data.BasePath = basePath
+			data.Nonce = nonce
+			if err := data.setOAuth2RedirectURL(c); err != nil {
+				return err
+			}
+			if err := data.setPreauthorization(c); err != nil {
+				return err
 			}
-			_, _ = c.Response().Write(doc)
-		default:
-			c.Request().URL.Path = matches[2]
-			http.FileServer(http.FS(swaggerFiles.FS)).ServeHTTP(c.Response(), c.Request())
+
+			return writeIndex(c, &data)
 		}
 
-		return nil
+		c.Request().URL.Path = path
+
+		return writeAsset(c, path)
 	}
 }
 
+// serveCompressed sets the Cache-Control of the Swagger UI asset name and serves its
+// compressed version if CompressAssets is set and the client accepts it.
+func (h *swaggerHandler) serveCompressed(c *echo.Context, name string) (bool, error) {
+	if h.config.AssetsCacheControl != "" {
+		c.Response().Header().Set("Cache-Control", h.config.AssetsCacheControl)
+	}
+
+	if h.config.CompressAssets {
+		return h.assets.serve(c, name)
+	}
+
+	return false, nil
+}
+
 const indexTemplate = `<!-- HTML for static distribution bundle build -->
 <!DOCTYPE html>
 <html lang="en">
 <head>
   <meta charset="UTF-8">
-  <title>Swagger UI</title>
-  <link rel="stylesheet" type="text/css" href="./swagger-ui.css" >
+  <title>{{.Title}}</title>
+  <link rel="stylesheet" type="text/css" href="{{.AssetURL "swagger-ui.css"}}"{{with .Integrity "swagger-ui.css"}} integrity="{{.}}" crossorigin="anonymous"{{end}} >
+  {{range .Stylesheets}}
+  <link rel="stylesheet" type="text/css" href="{{.}}" >
+  {{end}}
   <link rel="icon" type="image/png" href="./favicon-32x32.png" sizes="32x32" />
   <link rel="icon" type="image/png" href="./favicon-16x16.png" sizes="16x16" />
-  <style>
+  <style{{with .Nonce}} nonce="{{.}}"{{end}}>
     html
     {
         box-sizing: border-box;
@@ -298,12 +1190,41 @@ const indexTemplate = `<!-- HTML for static distribution bundle build -->
       margin:0;
       background: #fafafa;
     }
+
+    .swagger-ui-icons
+    {
+        position: absolute;
+        width: 0;
+        height: 0;
+    }
+    {{if .LogoURL}}
+    .swagger-ui .topbar .topbar-wrapper .link svg,
+    .swagger-ui .topbar .topbar-wrapper .link img
+    {
+        display: none;
+    }
+    .swagger-ui .topbar .topbar-wrapper .link:after
+    {
+        content: "";
+        display: block;
+        width: 160px;
+        height: 40px;
+        background: url("{{.LogoURL}}") no-repeat left center / contain;
+    }
+    {{end}}
+    {{if .HideTopbar}}
+    .swagger-ui .topbar
+    {
+        display: none;
+    }
+    {{end}}
+    {{.CustomCSS}}
   </style>
 </head>
 
 <body>
 
-<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" style="position:absolute;width:0;height:0">
+<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" class="swagger-ui-icons">
   <defs>
     <symbol viewBox="0 0 20 20" id="unlocked">
           <path d="M15.8 8H14V5.6C14 2.703 12.665 1 10 1 7.334 1 6 2.703 6 5.6V6h2v-.801C8 3.754 8.797 3 10 3c1.203 0 2 .754 2 2.199V8H4c-.553 0-1 .646-1 1.199V17c0 .549.428 1.139.951 1.307l1.197.387C5.672 18.861 6.55 19 7.1 19h5.8c.549 0 1.428-.139 1.951-.307l1.196-.387c.524-.167.953-.757.953-1.306V9.199C17 8.646 16.352 8 15.8 8z"></path>
@@ -339,42 +1260,59 @@ const indexTemplate = `<!-- HTML for static distribution bundle build -->
 
 <div id="{{.DomID}}"></div>
 
-<script src="./swagger-ui-bundle.js"> </script>
-<script src="./swagger-ui-standalone-preset.js"> </script>
-<script>
+<script src="{{.AssetURL "swagger-ui-bundle.js"}}"{{with .Integrity "swagger-ui-bundle.js"}} integrity="{{.}}" crossorigin="anonymous"{{end}}> </script>
+<script src="{{.AssetURL "swagger-ui-standalone-preset.js"}}"{{with .Integrity "swagger-ui-standalone-preset.js"}} integrity="{{.}}" crossorigin="anonymous"{{end}}> </script>
+{{range .Plugins}}
+<script src="{{.ScriptURL}}"{{with $.Nonce}} nonce="{{.}}"{{end}}> </script>
+{{end}}
+{{if or .Interceptors .TryItOutProxy}}
+<script src="./interceptors.js"> </script>
+{{end}}
+<script{{with .Nonce}} nonce="{{.}}"{{end}}>
 window.onload = function() {
   // Build a system
-  const ui = SwaggerUIBundle({
-	urls: [
-	{{range $index, $url := .URLs}}
-		{
-			name: "{{$url}}",
-			url: "{{$url}}",
-		},
-	{{end}}
-	],
-    syntaxHighlight: {{.SyntaxHighlight}},
-    deepLinking: {{.DeepLinking}},
-    docExpansion: "{{.DocExpansion}}",
-    persistAuthorization: {{.PersistAuthorization}},
-    dom_id: "#{{.DomID}}",
-    validatorUrl: null,
+  const ui = SwaggerUIBundle(Object.assign({{.UIConfig}}, {
     presets: [
       SwaggerUIBundle.presets.apis,
       SwaggerUIStandalonePreset
     ],
     plugins: [
-      SwaggerUIBundle.plugins.DownloadUrl
+      {{if .DownloadURLPlugin}}
+      SwaggerUIBundle.plugins.DownloadUrl,
+      {{end}}
+      {{range .Plugins}}
+      window[{{.Name}}],
+      {{end}}
     ],
-    layout: "StandaloneLayout"
-  })
-
-  {{if .OAuth}}
-  ui.initOAuth({
-    clientId: "{{.OAuth.ClientId}}",
-    realm: "{{.OAuth.Realm}}",
-    appName: "{{.OAuth.AppName}}"
-  })
+    {{with .Preauthorization}}
+    onComplete: function() {
+      var values = {{.}};
+      var schemes = ui.specSelectors.securityDefinitions();
+      Object.keys(values).forEach(function(name) {
+        var scheme = schemes && schemes.get(name);
+        if (!scheme) {
+          return;
+        }
+        if (scheme.get("type") === "basic" || (scheme.get("type") === "http" && scheme.get("scheme") === "basic")) {
+          var separator = values[name].indexOf(":");
+          if (separator < 0) {
+            separator = values[name].length;
+          }
+          ui.preauthorizeBasic(name, values[name].slice(0, separator), values[name].slice(separator + 1));
+        } else {
+          ui.preauthorizeApiKey(name, values[name]);
+        }
+      });
+    },
+    {{end}}
+    {{if or .Interceptors .TryItOutProxy}}
+    requestInterceptor: window.echoSwaggerInterceptors.request,
+    responseInterceptor: window.echoSwaggerInterceptors.response,
+    {{end}}
+  }))
+
+  {{with .InitOAuth}}
+  ui.initOAuth({{.}})
   {{end}}
 
   window.ui = ui
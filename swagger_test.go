@@ -1,10 +1,12 @@
 package echoSwagger
 
 import (
+	"html/template"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"testing/fstest"
 
 	"github.com/labstack/echo/v5"
 	"github.com/stretchr/testify/assert"
@@ -331,7 +333,277 @@ func TestConfig(t *testing.T) {
 
 	w := performRequest("GET", "/index.html", router)
 	assert.Equal(t, 200, w.Code)
-	assert.Contains(t, w.Body.String(), `url: "swagger.json"`)
+	assert.Contains(t, w.Body.String(), `"url":"swagger.json"`)
+}
+
+func TestConfigWithSpecURLs(t *testing.T) {
+	router := echo.New()
+
+	router.Any("/*", EchoWrapHandler(SpecURLs(
+		SpecURL{Name: "Public API v2", URL: "public.json"},
+		SpecURL{Name: "Admin API", URL: "admin.json", Primary: true},
+	)))
+
+	w := performRequest(http.MethodGet, "/index.html", router)
+	assert.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body, `"urls":[{"name":"doc.json","url":"doc.json"},{"name":"doc.yaml","url":"doc.yaml"},`+
+		`{"name":"Public API v2","url":"public.json"},{"name":"Admin API","url":"admin.json"}],"urls.primaryName":"Admin API",`)
+}
+
+func TestInstanceNamesHandler(t *testing.T) {
+	swag.Register("petstore", &mockedSwag{})
+	swag.Register("admin", &mockedSwag{})
+	swagV3.Register("petstore", &mockedSwag{})
+
+	for _, handler := range []echo.HandlerFunc{
+		EchoWrapHandler(InstanceNames("petstore", "admin")),
+		EchoWrapHandlerV3(InstanceNames("petstore")),
+	} {
+		router := echo.New()
+		router.GET("/swagger/*", handler)
+
+		w1 := performRequest(http.MethodGet, "/swagger/petstore/doc.json", router)
+		assert.Equal(t, http.StatusOK, w1.Code)
+		assert.Equal(t, "application/json; charset=utf-8", w1.Header().Get("Content-Type"))
+		assert.Equal(t, (&mockedSwag{}).ReadDoc(), w1.Body.String())
+
+		w2 := performRequest(http.MethodGet, "/swagger/petstore/doc.yaml", router)
+		assert.Equal(t, http.StatusOK, w2.Code)
+		assert.Contains(t, w2.Body.String(), "swagger: \"2.0\"")
+
+		assert.Equal(t, http.StatusNotFound, performRequest(http.MethodGet, "/swagger/unknown/doc.json", router).Code)
+
+		w3 := performRequest(http.MethodGet, "/swagger/index.html", router)
+		assert.Equal(t, http.StatusOK, w3.Code)
+		assert.Contains(t, w3.Body.String(), `{"name":"petstore","url":"petstore/doc.json"}`)
+	}
+
+	router := echo.New()
+	router.GET("/swagger/*", EchoWrapHandler(InstanceNames("petstore", "admin")))
+	assert.Equal(t, http.StatusOK, performRequest(http.MethodGet, "/swagger/admin/doc.json", router).Code)
+}
+
+func TestConfigWithIndexTemplate(t *testing.T) {
+	tmpl := template.Must(template.New("custom.html").Parse(
+		`<title>{{.PrimaryName}}</title><base href="{{.BasePath}}"><div id="{{.DomID}}"></div>{{range .Specs}}<a href="{{.URL}}">{{.Name}}</a>{{end}}`))
+
+	for _, wrap := range []func(options ...func(*Config)) echo.HandlerFunc{EchoWrapHandler, EchoWrapHandlerV3} {
+		router := echo.New()
+		router.GET("/admin/swagger/*", wrap(IndexTemplate(tmpl), URL("extra.json"), SpecURLs(SpecURL{Name: "Admin API", URL: "admin.json", Primary: true})))
+
+		w := performRequest(http.MethodGet, "/admin/swagger/index.html", router)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `<title>Admin API</title><base href="/admin/swagger/"><div id="swagger-ui"></div>`+
+			`<a href="doc.json">doc.json</a><a href="doc.yaml">doc.yaml</a><a href="extra.json">extra.json</a><a href="admin.json">Admin API</a>`,
+			w.Body.String())
+	}
+}
+
+func TestConfigWithIndexTemplateFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"templates/index.html":  {Data: []byte(`{{template "title" .}} {{.DocExpansion}}`)},
+		"templates/title.html":  {Data: []byte(`{{define "title"}}<h1>{{.BasePath}}</h1>{{end}}`)},
+		"templates/broken.html": {Data: []byte(`{{.DocExpansion`)},
+	}
+
+	router := echo.New()
+	router.GET("/swagger/*", EchoWrapHandler(IndexTemplateFS(fsys, "templates/index.html", "templates/title.html")))
+
+	w := performRequest(http.MethodGet, "/swagger/index.html", router)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `<h1>/swagger/</h1> list`, w.Body.String())
+
+	assert.Panics(t, func() { EchoWrapHandler(IndexTemplateFS(fsys, "templates/broken.html")) })
+	assert.Panics(t, func() { EchoWrapHandlerV3(IndexTemplateFS(fsys, "templates/missing.html")) })
+}
+
+func TestConfigWithBranding(t *testing.T) {
+	favicons := fstest.MapFS{
+		"favicon-32x32.png": {Data: []byte("custom favicon")},
+	}
+
+	for _, wrap := range []func(options ...func(*Config)) echo.HandlerFunc{EchoWrapHandler, EchoWrapHandlerV3} {
+		router := echo.New()
+		router.GET("/*", wrap(
+			Title("Acme API"),
+			Stylesheets("/static/acme.css"),
+			CustomCSS(".swagger-ui .topbar { background-color: #1b1b1b; }"),
+			LogoURL("/static/logo.svg"),
+			FaviconFS(favicons),
+		))
+
+		w1 := performRequest(http.MethodGet, "/index.html", router)
+		assert.Equal(t, http.StatusOK, w1.Code)
+		body := w1.Body.String()
+		assert.Contains(t, body, `<title>Acme API</title>`)
+		assert.Contains(t, body, `<link rel="stylesheet" type="text/css" href="/static/acme.css" >`)
+		assert.Contains(t, body, `.swagger-ui .topbar { background-color: #1b1b1b; }`)
+		assert.Contains(t, body, `background: url("/static/logo.svg") no-repeat left center / contain;`)
+
+		w2 := performRequest(http.MethodGet, "/favicon-32x32.png", router)
+		assert.Equal(t, http.StatusOK, w2.Code)
+		assert.Equal(t, "image/png", w2.Header().Get("Content-Type"))
+		assert.Equal(t, "custom favicon", w2.Body.String())
+
+		assert.Equal(t, http.StatusNotFound, performRequest(http.MethodGet, "/favicon-16x16.png", router).Code)
+	}
+
+	router := echo.New()
+	router.GET("/*", EchoWrapHandler())
+
+	body := performRequest(http.MethodGet, "/index.html", router).Body.String()
+	assert.Contains(t, body, `<title>Swagger UI</title>`)
+	assert.NotContains(t, body, `.topbar-wrapper .link:after`)
+}
+
+func TestConfigWithAssets(t *testing.T) {
+	dist := fstest.MapFS{
+		"swagger-ui.css":    {Data: []byte(".swagger-ui { color: red; }")},
+		"favicon-32x32.png": {Data: []byte("dist favicon")},
+	}
+
+	for _, wrap := range []func(options ...func(*Config)) echo.HandlerFunc{EchoWrapHandler, EchoWrapHandlerV3} {
+		router := echo.New()
+		router.GET("/*", wrap(AssetsFS(dist)))
+
+		w1 := performRequest(http.MethodGet, "/swagger-ui.css", router)
+		assert.Equal(t, http.StatusOK, w1.Code)
+		assert.Equal(t, "text/css; charset=utf-8", w1.Header().Get("Content-Type"))
+		assert.Equal(t, ".swagger-ui { color: red; }", w1.Body.String())
+
+		assert.Equal(t, "dist favicon", performRequest(http.MethodGet, "/favicon-32x32.png", router).Body.String())
+		assert.Equal(t, http.StatusNotFound, performRequest(http.MethodGet, "/swagger-ui-bundle.js", router).Code)
+	}
+
+	router := echo.New()
+	router.GET("/*", EchoWrapHandler(AssetsCDN("https://unpkg.com/swagger-ui-dist@5.17.14/", map[string]string{
+		"swagger-ui-bundle.js": "sha384-bundle",
+	})))
+
+	body := performRequest(http.MethodGet, "/index.html", router).Body.String()
+	assert.Contains(t, body, `<link rel="stylesheet" type="text/css" href="https://unpkg.com/swagger-ui-dist@5.17.14/swagger-ui.css" >`)
+	assert.Contains(t, body, `<script src="https://unpkg.com/swagger-ui-dist@5.17.14/swagger-ui-bundle.js" integrity="sha384-bundle" crossorigin="anonymous"> </script>`)
+	assert.Contains(t, body, `<script src="https://unpkg.com/swagger-ui-dist@5.17.14/swagger-ui-standalone-preset.js"> </script>`)
+	assert.Contains(t, body, `href="./favicon-32x32.png"`)
+
+	router = echo.New()
+	router.GET("/*", EchoWrapHandler())
+
+	body = performRequest(http.MethodGet, "/index.html", router).Body.String()
+	assert.Contains(t, body, `<link rel="stylesheet" type="text/css" href="./swagger-ui.css" >`)
+	assert.Contains(t, body, `<script src="./swagger-ui-bundle.js"> </script>`)
+}
+
+func TestConfigWithUIOptions(t *testing.T) {
+	router := echo.New()
+	router.GET("/*", EchoWrapHandler(
+		DefaultModelsExpandDepth(-1),
+		DisplayOperationID(true),
+		FilterText(`pets"</script>`),
+		MaxDisplayedTags(5),
+		SupportedSubmitMethods("get", "post"),
+		OperationsSorter("alpha"),
+		ValidatorURL("https://validator.swagger.io/validator"),
+	))
+
+	body := performRequest(http.MethodGet, "/index.html", router).Body.String()
+	assert.Contains(t, body, `"defaultModelsExpandDepth":-1,"defaultModelExpandDepth":1,"defaultModelRendering":"example",`)
+	assert.Contains(t, body, `"displayOperationId":true,`)
+	assert.Contains(t, body, `"filter":"pets\"\u003c/script\u003e",`)
+	assert.Contains(t, body, `"maxDisplayedTags":5,`)
+	assert.Contains(t, body, `"supportedSubmitMethods":["get","post"],"operationsSorter":"alpha",`)
+	assert.NotContains(t, body, `tagsSorter`)
+	assert.Contains(t, body, `"showMutatedRequest":true,`)
+	assert.Contains(t, body, `"validatorUrl":"https://validator.swagger.io/validator",`)
+	assert.NotContains(t, body, `</script>"`)
+
+	router = echo.New()
+	router.GET("/*", EchoWrapHandler(FilterBar(true)))
+
+	body = performRequest(http.MethodGet, "/index.html", router).Body.String()
+	assert.Contains(t, body, `"filter":true,`)
+	assert.Contains(t, body, `"validatorUrl":null,`)
+	assert.NotContains(t, body, `supportedSubmitMethods`)
+	assert.NotContains(t, body, `initOAuth`)
+
+	// Swagger UI reads its query configuration from the URL of the page.
+	for _, wrap := range []func(options ...func(*Config)) echo.HandlerFunc{EchoWrapHandler, EchoWrapHandlerV3} {
+		router = echo.New()
+		router.GET("/swagger/*", wrap(QueryConfigEnabled(true)))
+
+		w := performRequest(http.MethodGet, "/swagger/index.html?docExpansion=full&filter=pets", router)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"queryConfigEnabled":true,`)
+		assert.Contains(t, w.Body.String(), `src="./swagger-ui-bundle.js"`)
+	}
+}
+
+func TestConfigValidation(t *testing.T) {
+	assert.NoError(t, newConfig().checkFields())
+	assert.NoError(t, newConfig(DocExpansion("full"), OperationsSorter("method"), SupportedSubmitMethods("get", "trace")).checkFields())
+
+	assert.EqualError(t, newConfig(DocExpansion("https://github.com/swaggo/echo-swagger")).checkFields(),
+		`echoSwagger: invalid DocExpansion "https://github.com/swaggo/echo-swagger", want one of "list", "full", "none"`)
+	assert.Error(t, newConfig(DefaultModelRendering("schema")).checkFields())
+	assert.Error(t, newConfig(OperationsSorter("path")).checkFields())
+	assert.Error(t, newConfig(TagsSorter("method")).checkFields())
+	assert.Error(t, newConfig(SupportedSubmitMethods("GET")).checkFields())
+
+	// EchoWrapHandler and EchoWrapHandlerV3 serve the default value of unknown enum values.
+	for _, wrap := range []func(options ...func(*Config)) echo.HandlerFunc{EchoWrapHandler, EchoWrapHandlerV3} {
+		router := echo.New()
+		router.GET("/*", wrap(DocExpansion(""), DefaultModelRendering("schema"), OperationsSorter("path"), SupportedSubmitMethods("GET", "post")))
+
+		body := performRequest(http.MethodGet, "/index.html", router).Body.String()
+		assert.Contains(t, body, `"docExpansion":"list",`)
+		assert.Contains(t, body, `"defaultModelRendering":"example",`)
+		assert.Contains(t, body, `"supportedSubmitMethods":["post"],`)
+		assert.NotContains(t, body, `operationsSorter`)
+	}
+	assert.NotPanics(t, func() { EchoWrapHandler(DocExpansion(`list", alert(1), "`)) })
+
+	assert.EqualError(t, newConfig(DomID("#swagger-ui")).checkFields(),
+		`echoSwagger: invalid DomID "#swagger-ui", want a letter followed by letters, digits, - or _`)
+	assert.Error(t, newConfig(DomID(`ui"></div><script>`)).checkFields())
+	assert.EqualError(t, newConfig(URL("")).checkFields(), "echoSwagger: empty URL")
+	assert.EqualError(t, newConfig(SpecURLs(SpecURL{Name: "Admin API"})).checkFields(),
+		`echoSwagger: SpecURL{Name: "Admin API", URL: ""} requires a Name and a URL`)
+	assert.EqualError(t, newConfig(OAuth(&OAuthConfig{AppName: "My App"})).checkFields(), "echoSwagger: OAuth requires a ClientId")
+
+	// Incomplete configurations are only reported by New and NewV3.
+	assert.NotPanics(t, func() { EchoWrapHandler(OAuth(&OAuthConfig{AppName: "My App"})) })
+	assert.NotPanics(t, func() { EchoWrapHandlerV3(DomID("#swagger-ui"), URL("")) })
+}
+
+func TestNew(t *testing.T) {
+	swag.Register("new", &mockedSwag{})
+	swagV3.Register("new", &mockedSwag{})
+
+	for _, constructor := range []func(options ...func(*Config)) (echo.HandlerFunc, error){New, NewV3} {
+		handler, err := constructor(InstanceName("new"), InstanceNames("new"))
+		assert.NoError(t, err)
+
+		router := echo.New()
+		router.GET("/swagger/*", handler)
+		assert.Equal(t, http.StatusOK, performRequest(http.MethodGet, "/swagger/index.html", router).Code)
+		assert.Equal(t, http.StatusOK, performRequest(http.MethodGet, "/swagger/new/doc.json", router).Code)
+
+		_, err = constructor(InstanceName("new"), InstanceNames("unregistered"))
+		assert.EqualError(t, err, `echoSwagger: swag instance "unregistered" is not registered`)
+
+		_, err = constructor(InstanceName("unregistered"), Provider(StaticDoc([]byte((&mockedSwag{}).ReadDoc()))))
+		assert.NoError(t, err)
+
+		_, err = constructor(InstanceName("new"), DocExpansion("expanded"))
+		assert.EqualError(t, err, `echoSwagger: invalid DocExpansion "expanded", want one of "list", "full", "none"`)
+
+		_, err = constructor(InstanceName("new"), OAuth(&OAuthConfig{AppName: "My App"}))
+		assert.EqualError(t, err, "echoSwagger: OAuth requires a ClientId")
+
+		_, err = constructor(InstanceName("new"), IndexTemplateFS(fstest.MapFS{"broken.html": {Data: []byte(`{{.Title`)}}, "broken.html"))
+		assert.Error(t, err)
+	}
 }
 
 func TestConfigWithOAuth(t *testing.T) {
@@ -347,11 +619,33 @@ func TestConfigWithOAuth(t *testing.T) {
 	w := performRequest("GET", "/index.html", router)
 	assert.Equal(t, 200, w.Code)
 	body := w.Body.String()
-	assert.Contains(t, body, `ui.initOAuth({
-    clientId: "my-client-id",
-    realm: "my-realm",
-    appName: "My App Name"
-  })`)
+	assert.Contains(t, body, `ui.initOAuth({"clientId":"my-client-id","realm":"my-realm","appName":"My App Name"})`)
+	assert.Contains(t, body, `"oauth2RedirectUrl":"http://example.com/oauth2-redirect.html"`)
+}
+
+func TestConfigWithOAuthPKCE(t *testing.T) {
+	router := echo.New()
+
+	router.GET("/*", EchoWrapHandler(
+		OAuth(&OAuthConfig{
+			ClientId:                          "my-client-id",
+			AppName:                           `My "App"</script>`,
+			Scopes:                            []string{"openid", "pets:read"},
+			ScopeSeparator:                    ",",
+			AdditionalQueryStringParams:       map[string]string{"audience": "https://api.example.com"},
+			UsePkceWithAuthorizationCodeGrant: true,
+		}),
+		OAuth2RedirectURL("https://docs.example.com/swagger/oauth2-redirect.html"),
+	))
+
+	w := performRequest(http.MethodGet, "/index.html", router)
+	assert.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body, `ui.initOAuth({"clientId":"my-client-id","realm":"","appName":"My \"App\"\u003c/script\u003e",`+
+		`"scopes":["openid","pets:read"],"scopeSeparator":",","additionalQueryStringParams":{"audience":"https://api.example.com"},`+
+		`"usePkceWithAuthorizationCodeGrant":true})`)
+	assert.Contains(t, body, `"oauth2RedirectUrl":"https://docs.example.com/swagger/oauth2-redirect.html"`)
+	assert.NotContains(t, body, `clientSecret`)
 }
 
 func TestHandlerReuse(t *testing.T) {
@@ -413,6 +707,116 @@ func TestURL(t *testing.T) {
 	assert.Equal(t, expected, cfg.URLs[0])
 }
 
+func TestSpecURLs(t *testing.T) {
+	var cfg Config
+	expected := []SpecURL{{Name: "Admin API", URL: "admin.json", Primary: true}}
+	SpecURLs(expected...)(&cfg)
+	assert.Equal(t, expected, cfg.SpecURLs)
+
+	data, err := newIndexData(newConfig(SpecURLs(expected...)))
+	assert.NoError(t, err)
+	assert.Equal(t, []SpecURL{
+		{Name: "doc.json", URL: "doc.json"},
+		{Name: "doc.yaml", URL: "doc.yaml"},
+		{Name: "Admin API", URL: "admin.json", Primary: true},
+	}, data.Specs)
+	assert.Equal(t, "Admin API", data.PrimaryName)
+
+	data, err = newIndexData(newConfig())
+	assert.NoError(t, err)
+	assert.Empty(t, data.PrimaryName)
+}
+
+func TestIndexTemplate(t *testing.T) {
+	var cfg Config
+	expected := template.New("custom.html")
+	IndexTemplate(expected)(&cfg)
+	assert.Equal(t, expected, cfg.IndexTemplate)
+
+	index, err := cfg.parseIndexTemplate()
+	assert.NoError(t, err)
+	assert.Equal(t, expected, index)
+
+	index, err = newConfig().parseIndexTemplate()
+	assert.NoError(t, err)
+	assert.Equal(t, "swagger_index.html", index.Name())
+}
+
+func TestBranding(t *testing.T) {
+	var cfg Config
+	Title("Acme API")(&cfg)
+	Stylesheets("a.css", "b.css")(&cfg)
+	CustomCSS("body { margin: 0; }")(&cfg)
+	LogoURL("logo.svg")(&cfg)
+	FaviconFS(fstest.MapFS{})(&cfg)
+
+	assert.Equal(t, "Acme API", cfg.Title)
+	assert.Equal(t, []string{"a.css", "b.css"}, cfg.Stylesheets)
+	assert.Equal(t, template.CSS("body { margin: 0; }"), cfg.CustomCSS)
+	assert.Equal(t, "logo.svg", cfg.LogoURL)
+	assert.NotNil(t, cfg.FaviconFS)
+}
+
+func TestAssets(t *testing.T) {
+	var cfg Config
+	AssetsFS(fstest.MapFS{})(&cfg)
+	AssetsCDN("https://cdn.example.com/swagger-ui", map[string]string{"swagger-ui.css": "sha384-css"})(&cfg)
+
+	assert.NotNil(t, cfg.AssetsFS)
+	assert.Equal(t, "https://cdn.example.com/swagger-ui", cfg.AssetsBaseURL)
+	assert.Equal(t, map[string]string{"swagger-ui.css": "sha384-css"}, cfg.AssetsIntegrity)
+
+	data, err := newIndexData(&cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://cdn.example.com/swagger-ui/swagger-ui.css", data.AssetURL("swagger-ui.css"))
+	assert.Equal(t, "sha384-css", data.Integrity("swagger-ui.css"))
+	assert.Empty(t, data.Integrity("swagger-ui-bundle.js"))
+}
+
+func TestUIOptions(t *testing.T) {
+	cfg := newConfig(
+		DefaultModelsExpandDepth(2),
+		DefaultModelExpandDepth(3),
+		DefaultModelRendering("model"),
+		DisplayOperationID(true),
+		DisplayRequestDuration(true),
+		FilterBar(true),
+		FilterText("pets"),
+		MaxDisplayedTags(10),
+		ShowExtensions(true),
+		ShowCommonExtensions(true),
+		TryItOutEnabled(true),
+		SupportedSubmitMethods("get"),
+		OperationsSorter("method"),
+		TagsSorter("alpha"),
+		RequestSnippetsEnabled(true),
+		ShowMutatedRequest(false),
+		WithCredentials(true),
+		QueryConfigEnabled(true),
+		ValidatorURL("https://validator.example.com"),
+	)
+
+	assert.Equal(t, 2, cfg.DefaultModelsExpandDepth)
+	assert.Equal(t, 3, cfg.DefaultModelExpandDepth)
+	assert.Equal(t, "model", cfg.DefaultModelRendering)
+	assert.True(t, cfg.DisplayOperationID)
+	assert.True(t, cfg.DisplayRequestDuration)
+	assert.True(t, cfg.FilterBar)
+	assert.Equal(t, "pets", cfg.FilterText)
+	assert.Equal(t, 10, cfg.MaxDisplayedTags)
+	assert.True(t, cfg.ShowExtensions)
+	assert.True(t, cfg.ShowCommonExtensions)
+	assert.True(t, cfg.TryItOutEnabled)
+	assert.Equal(t, []string{"get"}, cfg.SupportedSubmitMethods)
+	assert.Equal(t, "method", cfg.OperationsSorter)
+	assert.Equal(t, "alpha", cfg.TagsSorter)
+	assert.True(t, cfg.RequestSnippetsEnabled)
+	assert.False(t, cfg.ShowMutatedRequest)
+	assert.True(t, cfg.WithCredentials)
+	assert.True(t, cfg.QueryConfigEnabled)
+	assert.Equal(t, "https://validator.example.com", cfg.ValidatorURL)
+}
+
 func TestDeepLinking(t *testing.T) {
 	var cfg Config
 	expected := true
@@ -463,6 +867,24 @@ func TestInstanceNameV3(t *testing.T) {
 	assert.Equal(t, swagV3.Name, newCfg.InstanceName)
 }
 
+func TestInstanceNames(t *testing.T) {
+	var cfg Config
+	expected := []string{"petstore", "admin"}
+	InstanceNames(expected...)(&cfg)
+	assert.Equal(t, expected, cfg.InstanceNames)
+
+	newCfg := newConfig(InstanceNames(expected...))
+	for wildcard, expected := range map[string]string{
+		"doc.json":           "swagger",
+		"admin/doc.yaml":     "admin",
+		"/petstore/doc.json": "petstore",
+		"unknown/doc.json":   "",
+	} {
+		instanceName, _ := newCfg.docInstance(wildcard)
+		assert.Equal(t, expected, instanceName, wildcard)
+	}
+}
+
 func TestPersistAuthorization(t *testing.T) {
 	var cfg Config
 	expected := true
@@ -483,6 +905,13 @@ func TestOAuth(t *testing.T) {
 	assert.Equal(t, expected.AppName, cfg.OAuth.AppName)
 }
 
+func TestOAuth2RedirectURL(t *testing.T) {
+	var cfg Config
+	expected := "https://docs.example.com/swagger/oauth2-redirect.html"
+	OAuth2RedirectURL(expected)(&cfg)
+	assert.Equal(t, expected, cfg.OAuth2RedirectURL)
+}
+
 func TestOAuthNil(t *testing.T) {
 	var cfg Config
 	var expected *OAuthConfig
@@ -0,0 +1,237 @@
+package echoSwagger
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// uiConfig is the configuration passed to SwaggerUIBundle. See
+// https://swagger.io/docs/open-source-tools/swagger-ui/usage/configuration/ for further details.
+type uiConfig struct {
+	URL                      string   `json:"url,omitempty"`
+	URLs                     []uiURL  `json:"urls,omitempty"`
+	PrimaryName              string   `json:"urls.primaryName,omitempty"`
+	DomID                    string   `json:"dom_id"`
+	SyntaxHighlight          bool     `json:"syntaxHighlight"`
+	DeepLinking              bool     `json:"deepLinking"`
+	DocExpansion             string   `json:"docExpansion"`
+	PersistAuthorization     bool     `json:"persistAuthorization"`
+	DefaultModelsExpandDepth int      `json:"defaultModelsExpandDepth"`
+	DefaultModelExpandDepth  int      `json:"defaultModelExpandDepth"`
+	DefaultModelRendering    string   `json:"defaultModelRendering"`
+	DisplayOperationID       bool     `json:"displayOperationId"`
+	DisplayRequestDuration   bool     `json:"displayRequestDuration"`
+	Filter                   any      `json:"filter"`
+	MaxDisplayedTags         int      `json:"maxDisplayedTags,omitempty"`
+	ShowExtensions           bool     `json:"showExtensions"`
+	ShowCommonExtensions     bool     `json:"showCommonExtensions"`
+	TryItOutEnabled          bool     `json:"tryItOutEnabled"`
+	SupportedSubmitMethods   []string `json:"supportedSubmitMethods,omitempty"`
+	OperationsSorter         string   `json:"operationsSorter,omitempty"`
+	TagsSorter               string   `json:"tagsSorter,omitempty"`
+	RequestSnippetsEnabled   bool     `json:"requestSnippetsEnabled"`
+	ShowMutatedRequest       bool     `json:"showMutatedRequest"`
+	WithCredentials          bool     `json:"withCredentials"`
+	QueryConfigEnabled       bool     `json:"queryConfigEnabled"`
+	ValidatorURL             *string  `json:"validatorUrl"`
+	OAuth2RedirectURL        string   `json:"oauth2RedirectUrl,omitempty"`
+	Layout                   string   `json:"layout"`
+}
+
+// uiURL is an entry of the spec selector.
+type uiURL struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+func newUIConfig(data *IndexData) *uiConfig {
+	config := data.Config
+	ui := &uiConfig{
+		URLs:                     make([]uiURL, 0, len(data.Specs)),
+		PrimaryName:              data.PrimaryName,
+		DomID:                    "#" + config.DomID,
+		SyntaxHighlight:          config.SyntaxHighlight,
+		DeepLinking:              config.DeepLinking,
+		DocExpansion:             enumValue(config.DocExpansion, docExpansions, "list"),
+		PersistAuthorization:     config.PersistAuthorization,
+		DefaultModelsExpandDepth: config.DefaultModelsExpandDepth,
+		DefaultModelExpandDepth:  config.DefaultModelExpandDepth,
+		DefaultModelRendering:    enumValue(config.DefaultModelRendering, defaultModelRenderings, "example"),
+		DisplayOperationID:       config.DisplayOperationID,
+		DisplayRequestDuration:   config.DisplayRequestDuration,
+		Filter:                   config.FilterBar,
+		MaxDisplayedTags:         config.MaxDisplayedTags,
+		ShowExtensions:           config.ShowExtensions,
+		ShowCommonExtensions:     config.ShowCommonExtensions,
+		TryItOutEnabled:          config.TryItOutEnabled,
+		OperationsSorter:         enumValue(config.OperationsSorter, operationsSorters, ""),
+		TagsSorter:               enumValue(config.TagsSorter, tagsSorters, ""),
+		RequestSnippetsEnabled:   config.RequestSnippetsEnabled,
+		ShowMutatedRequest:       config.ShowMutatedRequest,
+		WithCredentials:          config.WithCredentials,
+		QueryConfigEnabled:       config.QueryConfigEnabled,
+		OAuth2RedirectURL:        config.OAuth2RedirectURL,
+		Layout:                   enumValue(config.Layout, layouts, "StandaloneLayout"),
+	}
+
+	for _, spec := range data.Specs {
+		ui.URLs = append(ui.URLs, uiURL{Name: spec.Name, URL: spec.URL})
+	}
+	// Swagger UI only loads one of urls from the topbar of StandaloneLayout, so BaseLayout
+	// loads the primary spec from url.
+	if ui.Layout == "BaseLayout" && len(ui.URLs) > 0 {
+		ui.URL = ui.URLs[0].URL
+		for _, spec := range ui.URLs {
+			if spec.Name == ui.PrimaryName {
+				ui.URL = spec.URL
+			}
+		}
+		ui.URLs, ui.PrimaryName = nil, ""
+	}
+	for _, method := range config.SupportedSubmitMethods {
+		if slices.Contains(submitMethods, method) {
+			ui.SupportedSubmitMethods = append(ui.SupportedSubmitMethods, method)
+		}
+	}
+	if config.FilterText != "" {
+		ui.Filter = config.FilterText
+	}
+	if config.ValidatorURL != "" {
+		ui.ValidatorURL = &config.ValidatorURL
+	}
+
+	return ui
+}
+
+// The values accepted by the enum fields of Config.
+var (
+	docExpansions          = []string{"list", "full", "none"}
+	defaultModelRenderings = []string{"example", "model"}
+	operationsSorters      = []string{"", "alpha", "method"}
+	tagsSorters            = []string{"", "alpha"}
+	submitMethods          = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+	layouts                = []string{"StandaloneLayout", "BaseLayout"}
+)
+
+// domIDPattern matches the ids usable as a CSS selector without escaping.
+var domIDPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]*$`)
+
+// validate reports the first invalid plugin of config.
+func (config *Config) validate() error {
+	// The DownloadUrl plugin provides the action loading the API definition, which only
+	// one of Plugins can replace.
+	if !config.DownloadURLPlugin && len(config.Plugins) == 0 {
+		return errors.New("echoSwagger: DownloadURLPlugin(false) requires a Plugin loading the API definition")
+	}
+
+	return config.validatePlugins()
+}
+
+// checkFields reports the first field of config holding a value Swagger UI does not accept,
+// or left empty or malformed. Only New and NewV3 run it, so that the configurations
+// EchoWrapHandler and EchoWrapHandlerV3 accepted before keep working: they serve the default
+// value of invalid enum fields instead.
+func (config *Config) checkFields() error {
+	if err := validateEnum("DocExpansion", config.DocExpansion, docExpansions); err != nil {
+		return err
+	}
+	if err := validateEnum("DefaultModelRendering", config.DefaultModelRendering, defaultModelRenderings); err != nil {
+		return err
+	}
+	if err := validateEnum("OperationsSorter", config.OperationsSorter, operationsSorters); err != nil {
+		return err
+	}
+	if err := validateEnum("TagsSorter", config.TagsSorter, tagsSorters); err != nil {
+		return err
+	}
+	for _, method := range config.SupportedSubmitMethods {
+		if err := validateEnum("SupportedSubmitMethods", method, submitMethods); err != nil {
+			return err
+		}
+	}
+	if err := validateEnum("Layout", config.Layout, layouts); err != nil {
+		return err
+	}
+	if !domIDPattern.MatchString(config.DomID) {
+		return fmt.Errorf("echoSwagger: invalid DomID %q, want a letter followed by letters, digits, - or _", config.DomID)
+	}
+	for _, url := range config.URLs {
+		if url == "" {
+			return errors.New("echoSwagger: empty URL")
+		}
+	}
+	for _, spec := range config.SpecURLs {
+		if spec.Name == "" || spec.URL == "" {
+			return fmt.Errorf("echoSwagger: SpecURL{Name: %q, URL: %q} requires a Name and a URL", spec.Name, spec.URL)
+		}
+	}
+	if config.OAuth != nil && config.OAuth.ClientId == "" {
+		return errors.New("echoSwagger: OAuth requires a ClientId")
+	}
+	for _, allowed := range config.AllowedIPs {
+		if _, err := parsePrefix(allowed); err != nil {
+			return fmt.Errorf("echoSwagger: invalid AllowedIPs entry %q, want an IP address or a CIDR range", allowed)
+		}
+	}
+
+	return nil
+}
+
+// instances returns InstanceName and InstanceNames.
+func (config *Config) instances() []string {
+	return append([]string{config.InstanceName}, config.InstanceNames...)
+}
+
+// checkInstances reports the first of InstanceName and InstanceNames which is not
+// registered.
+func (config *Config) checkInstances(registered func(name string) bool) error {
+	for _, name := range config.instances() {
+		if !registered(name) {
+			return fmt.Errorf("echoSwagger: swag instance %q is not registered", name)
+		}
+	}
+
+	return nil
+}
+
+// enumValue returns value if it is one of values, and fallback otherwise.
+func enumValue(value string, values []string, fallback string) string {
+	if slices.Contains(values, value) {
+		return value
+	}
+
+	return fallback
+}
+
+func validateEnum(field, value string, values []string) error {
+	if slices.Contains(values, value) {
+		return nil
+	}
+
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = fmt.Sprintf("%q", v)
+	}
+
+	return fmt.Errorf("echoSwagger: invalid %s %q, want one of %s", field, value, strings.Join(quoted, ", "))
+}
+
+// uiScripts returns the configuration of SwaggerUIBundle and of ui.initOAuth, if any, as
+// JavaScript literals.
+func uiScripts(data *IndexData) (ui, initOAuth template.JS, err error) {
+	if ui, err = jsonJS(data.ui); err != nil {
+		return "", "", err
+	}
+
+	if data.OAuth != nil {
+		if initOAuth, err = jsonJS(data.OAuth); err != nil {
+			return "", "", err
+		}
+	}
+
+	return ui, initOAuth, nil
+}